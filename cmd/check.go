@@ -6,6 +6,7 @@ import (
 	"io"
 	"time"
 
+	"github.com/opdev/opcap/internal/bundle"
 	"github.com/opdev/opcap/internal/capability"
 	"github.com/opdev/opcap/internal/operator"
 	"k8s.io/client-go/rest"
@@ -22,6 +23,10 @@ type checkCommandFlags struct {
 	AllInstallModes        bool     `json:"allInstallModes"`
 	ExtraCRDirectory       string   `json:"extraCRDirectory"`
 	DetailedReports        bool     `json:"detailedReports"`
+	ScaleFieldPath         string   `json:"scaleFieldPath"`
+	AllowedRegistries      []string `json:"allowedRegistries"`
+	BundlesDir             string   `json:"bundlesDir"`
+	SkipUnsupported        bool     `json:"skipUnsupported"`
 }
 
 var checkflags checkCommandFlags
@@ -36,7 +41,14 @@ requirements for Operator Capabilities Level to attest operator
 advanced features by running custom resources provided by CSVs
 and/or users.`,
 		Example: "opcap check --catalogsource=certified-operators --catalogsourcenamespace=openshift-marketplace",
-		RunE:    checkRunE,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// without bundles the supported versions of every package are unknown and nothing would be skipped
+			if checkflags.SkipUnsupported && checkflags.BundlesDir == "" {
+				return fmt.Errorf("--skip-unsupported requires --bundles-dir")
+			}
+			return nil
+		},
+		RunE: checkRunE,
 	}
 
 	defaultAuditPlan := []string{"OperatorInstall"}
@@ -47,12 +59,20 @@ and/or users.`,
 		"specifies the catalogsource to test against")
 	flags.StringVar(&checkflags.CatalogSourceNamespace, "catalogsourcenamespace", "openshift-marketplace",
 		"specifies the namespace where the catalogsource exists")
-	flags.StringSliceVar(&checkflags.AuditPlan, "audit-plan", defaultAuditPlan, "audit plan is the ordered list of operator test functions to be called during a capability audit.")
+	flags.StringSliceVar(&checkflags.AuditPlan, "audit-plan", defaultAuditPlan, "audit plan is the ordered list of operator test functions to be called during a capability audit. OperatorResidue must come before OperatorInstall.")
 	flags.StringSliceVar(&checkflags.Packages, "packages", []string{}, "a list of package(s) which limits audits and/or other flag(s) output")
 	flags.BoolVar(&checkflags.AllInstallModes, "all-installmodes", false, "when set, all install modes supported by an operator will be tested")
 	flags.StringVar(&checkflags.ExtraCRDirectory, "extra-cr-directory", "",
 		"directory containing the additional Custom Resources to be deployed by the OperandInstall audit. The manifest files should be located in subdirectories named after the packages they are corresponding to.")
 	flags.BoolVar(&checkflags.DetailedReports, "detailed-reports", false, "when set, a debug report will be created with events and logs for the tests being run")
+	flags.StringVar(&checkflags.ScaleFieldPath, "scale-field", "",
+		"dot separated path to the replica-like field of operands scaled by the OperandScale audit, e.g. spec.cluster.size. spec.replicas, spec.size and spec.nodes are tried otherwise")
+	flags.StringSliceVar(&checkflags.AllowedRegistries, "allowed-registries", []string{},
+		"registries, optionally followed by a repository prefix such as quay.io/myorg, images are allowed to be pulled from. All registries are allowed when empty")
+	flags.StringVar(&checkflags.BundlesDir, "bundles-dir", "",
+		"directory containing the operator bundles, e.g. a clone of an operators repository, read for the OpenShift versions packages declare support for")
+	flags.BoolVar(&checkflags.SkipUnsupported, "skip-unsupported", false,
+		"when set, packages whose bundle does not declare support for the OpenShift version of the cluster are skipped. Requires --bundles-dir")
 
 	return cmd
 }
@@ -74,6 +94,15 @@ func checkRunE(cmd *cobra.Command, args []string) error {
 }
 
 func runAudits(ctx context.Context, kubeconfig *rest.Config, client operator.Client, fs afero.Fs, reportWriter io.Writer) error {
+	var bundles []bundle.Bundle
+	if checkflags.BundlesDir != "" {
+		var err error
+		bundles, err = bundle.ReadBundlesFromDir(checkflags.BundlesDir)
+		if err != nil {
+			return fmt.Errorf("could not read bundles: %v", err)
+		}
+	}
+
 	// run all dynamically built audits in the auditor workqueue
 	if err := capability.RunAudits(ctx,
 		capability.WithAuditPlan(checkflags.AuditPlan),
@@ -87,6 +116,10 @@ func runAudits(ctx context.Context, kubeconfig *rest.Config, client operator.Cli
 		capability.WithTimeout(2*time.Minute),
 		capability.WithReportWriter(reportWriter),
 		capability.WithDetailedReports(checkflags.DetailedReports),
+		capability.WithScaleFieldPath(checkflags.ScaleFieldPath),
+		capability.WithAllowedRegistries(checkflags.AllowedRegistries),
+		capability.WithBundles(bundles),
+		capability.WithSkipUnsupported(checkflags.SkipUnsupported),
 	); err != nil {
 		return err
 	}
@@ -29,6 +29,12 @@ var _ = Describe("Check command tests", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("please provide kubeconfig"))
 		})
+		It("should reject --skip-unsupported without --bundles-dir", func() {
+			savedFlags := checkflags
+			DeferCleanup(func() { checkflags = savedFlags })
+			_, err := executeCommand(checkCmd(), "--skip-unsupported")
+			Expect(err).To(MatchError("--skip-unsupported requires --bundles-dir"))
+		})
 	})
 
 	When("running audits", func() {
@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func diffCmd() *cobra.Command {
+	// Run is empty. Otherwise, on an error, it would not be marked
+	// as Runnable, which would not print out the usage/help.
+	cmd := cobra.Command{
+		Use:   "diff",
+		Short: "Diff commands",
+		Long:  "Commands that compare operator artifacts offline, without a cluster",
+	}
+
+	cmd.AddCommand(diffBundlesCmd())
+	cmd.AddCommand(diffCRDsCmd())
+
+	return &cmd
+}
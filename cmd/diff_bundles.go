@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/opdev/opcap/internal/bundle"
+
+	"github.com/spf13/cobra"
+)
+
+var diffBundlesFlags bundlesSource
+
+func diffBundlesCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "bundles <package> <from-version> <to-version>",
+		Short: "Show what changed between two bundles of a package",
+		Long: `The 'diff bundles' command compares two bundles of a package and shows the changes in
+permissions, CRDs and their schemas, install modes, related images and alm-examples.
+Changes that can break existing users, such as removed CRD versions or fields and
+install modes no longer supported, are flagged as breaking. Versions are matched
+against the bundle version directories, the CSV names and the CSV versions.`,
+		Example: "opcap diff bundles my-operator 1.0.0 1.1.0 --from-dir operators-repo",
+		Args:    cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return diffBundles(args[0], args[1], args[2], cmd.OutOrStdout())
+		},
+	}
+
+	diffBundlesFlags.addFlags(cmd.Flags())
+
+	cmd.MarkFlagsMutuallyExclusive("from-dir", "from-repo")
+
+	return &cmd
+}
+
+func diffBundles(packageName, fromVersion, toVersion string, out io.Writer) error {
+	bundles, err := diffBundlesFlags.readBundles()
+	if err != nil {
+		return err
+	}
+
+	from, ok := bundle.FindBundle(bundles, packageName, fromVersion)
+	if !ok {
+		return fmt.Errorf("no bundle %s found for package %s", fromVersion, packageName)
+	}
+	to, ok := bundle.FindBundle(bundles, packageName, toVersion)
+	if !ok {
+		return fmt.Errorf("no bundle %s found for package %s", toVersion, packageName)
+	}
+
+	diff := bundle.DiffBundles(from, to)
+	if len(diff.Changes) == 0 {
+		fmt.Fprintf(out, "No changes from %s to %s\n", diff.From, diff.To)
+		return nil
+	}
+	printBundleDiff(diff, out)
+	return nil
+}
+
+func printBundleDiff(diff bundle.BundleDiff, out io.Writer) {
+	headings := "Category\tChange\tSubject\tDetail\tBreaking"
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, headings)
+	for _, change := range diff.Changes {
+		breaking := ""
+		if change.Breaking {
+			breaking = "yes"
+		}
+		fmt.Fprintln(w, strings.Join([]string{change.Category, change.Change, change.Subject, change.Detail, breaking}, "\t"))
+	}
+	w.Flush()
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	. "github.com/onsi/ginkgo/v2/dsl/core"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Diff Bundles Cmd", func() {
+	const bundlesDir = "--from-dir=../internal/bundle/testdata/diff"
+
+	When("Calling opcap diff bundles", func() {
+		It("should print the changes between two versions", func() {
+			out, err := executeCommand(diffBundlesCmd(), bundlesDir, "widget-operator", "1.0.0", "2.0.0")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(ContainSubstring("Category"))
+			Expect(out).To(MatchRegexp(`crd\s+removed\s+widgets.example.com v1\s+field .spec.color removed\s+yes`))
+			Expect(out).To(MatchRegexp(`install mode\s+removed\s+OwnNamespace\s+yes`))
+		})
+		It("should print that nothing changed between identical versions", func() {
+			out, err := executeCommand(diffBundlesCmd(), bundlesDir, "widget-operator", "2.0.0", "widget-operator.v2.0.0")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(ContainSubstring("No changes"))
+		})
+		It("should fail on an unknown version", func() {
+			_, err := executeCommand(diffBundlesCmd(), bundlesDir, "widget-operator", "1.0.0", "3.0.0")
+			Expect(err).To(MatchError("no bundle 3.0.0 found for package widget-operator"))
+		})
+		It("should require a package and two versions", func() {
+			_, err := executeCommand(diffBundlesCmd(), bundlesDir, "widget-operator", "1.0.0")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/opdev/opcap/internal/bundle"
+
+	"github.com/spf13/cobra"
+)
+
+var diffCRDsFlags bundlesSource
+
+func diffCRDsCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "crds <package> <from-version> <to-version>",
+		Short: "Show the upgrade risk of the CRDs of a package between two bundles",
+		Long: `The 'diff crds' command compares the CRDs of two bundles of a package and reports the
+changes that can break existing custom resources or their clients on upgrade: removed
+CRDs, removed versions that were served or stored, versions no longer served, removed
+properties, new required fields, type changes, narrowed enums and tightened validation:
+lowered maximums, raised minimums, stricter lengths and item counts, new or changed
+patterns and newly exclusive bounds. Each CRD is given the upgrade risk of its riskiest
+change, none, low, medium or high.`,
+		Example: "opcap diff crds my-operator 1.0.0 1.1.0 --from-dir operators-repo",
+		Args:    cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return diffCRDs(args[0], args[1], args[2], cmd.OutOrStdout())
+		},
+	}
+
+	diffCRDsFlags.addFlags(cmd.Flags())
+
+	cmd.MarkFlagsMutuallyExclusive("from-dir", "from-repo")
+
+	return &cmd
+}
+
+func diffCRDs(packageName, fromVersion, toVersion string, out io.Writer) error {
+	bundles, err := diffCRDsFlags.readBundles()
+	if err != nil {
+		return err
+	}
+
+	from, ok := bundle.FindBundle(bundles, packageName, fromVersion)
+	if !ok {
+		return fmt.Errorf("no bundle %s found for package %s", fromVersion, packageName)
+	}
+	to, ok := bundle.FindBundle(bundles, packageName, toVersion)
+	if !ok {
+		return fmt.Errorf("no bundle %s found for package %s", toVersion, packageName)
+	}
+
+	risks := bundle.AnalyzeCRDs(from.CRDs, to.CRDs)
+	if len(risks) == 0 {
+		fmt.Fprintf(out, "No CRDs in %s\n", from.StartingCSV)
+		return nil
+	}
+	printCRDRisks(risks, out)
+	return nil
+}
+
+func printCRDRisks(risks []bundle.CRDRisk, out io.Writer) {
+	headings := "CRD\tUpgrade Risk\tVersion\tChange Risk\tChange"
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, headings)
+	for _, risk := range risks {
+		if len(risk.Changes) == 0 {
+			fmt.Fprintln(w, strings.Join([]string{risk.Name, risk.Risk, "", "", ""}, "\t"))
+		}
+		for _, change := range risk.Changes {
+			fmt.Fprintln(w, strings.Join([]string{risk.Name, risk.Risk, change.Version, change.Risk, change.Detail}, "\t"))
+		}
+	}
+	w.Flush()
+}
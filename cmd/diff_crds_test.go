@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	. "github.com/onsi/ginkgo/v2/dsl/core"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Diff CRDs Cmd", func() {
+	const bundlesDir = "--from-dir=../internal/bundle/testdata/diff"
+
+	When("Calling opcap diff crds", func() {
+		It("should print the upgrade risk of every CRD", func() {
+			out, err := executeCommand(diffCRDsCmd(), bundlesDir, "widget-operator", "1.0.0", "2.0.0")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(MatchRegexp(`widgets.example.com\s+high\s+v1alpha1\s+high\s+served version removed`))
+			Expect(out).To(MatchRegexp(`widgets.example.com\s+high\s+v1\s+high\s+field .spec.mode is now required`))
+		})
+		It("should print CRDs without risk", func() {
+			out, err := executeCommand(diffCRDsCmd(), bundlesDir, "widget-operator", "2.0.0", "2.0.0")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(MatchRegexp(`gadgets.example.com\s+none`))
+		})
+		It("should fail on an unknown version", func() {
+			_, err := executeCommand(diffCRDsCmd(), bundlesDir, "widget-operator", "0.1.0", "2.0.0")
+			Expect(err).To(MatchError("no bundle 0.1.0 found for package widget-operator"))
+		})
+	})
+})
@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func lintCmd() *cobra.Command {
+	// Run is empty. Otherwise, on an error, it would not be marked
+	// as Runnable, which would not print out the usage/help.
+	cmd := cobra.Command{
+		Use:   "lint",
+		Short: "Lint commands",
+		Long:  "Commands that check operator artifacts offline, without a cluster",
+	}
+
+	cmd.AddCommand(lintBundleCmd())
+
+	return &cmd
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/opdev/opcap/internal/bundle"
+
+	"github.com/spf13/cobra"
+)
+
+func lintBundleCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "bundle <dir>",
+		Short: "Check a bundle directory for structural problems",
+		Long: `The 'lint bundle' command parses the CSV, CRDs, annotations.yaml and other manifests
+of a bundle directory and reports structural problems such as invalid alm-examples,
+missing install modes, CRDs missing from the manifests directory or mismatched
+package and channel annotations. No cluster is needed.`,
+		Example: "opcap lint bundle operators/my-operator/1.0.0",
+		Args:    cobra.ExactArgs(1),
+		// problems found in the bundle aren't usage errors
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return lintBundle(args[0], cmd.OutOrStdout())
+		},
+	}
+
+	return &cmd
+}
+
+func lintBundle(bundleDir string, out io.Writer) error {
+	problems, err := bundle.LintBundle(bundleDir)
+	if err != nil {
+		return err
+	}
+
+	if len(problems) == 0 {
+		fmt.Fprintf(out, "No problems found in bundle %s\n", bundleDir)
+		return nil
+	}
+
+	errorCount := printLintProblems(problems, out)
+	if errorCount > 0 {
+		return fmt.Errorf("bundle %s has %d error(s)", bundleDir, errorCount)
+	}
+	return nil
+}
+
+// printLintProblems prints the problems and returns how many of them are errors
+func printLintProblems(problems []bundle.LintProblem, out io.Writer) int {
+	errorCount := 0
+	headings := "Severity\tFile\tMessage"
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, headings)
+	for _, problem := range problems {
+		if problem.Severity == bundle.LintError {
+			errorCount++
+		}
+		fmt.Fprintln(w, strings.Join([]string{problem.Severity, problem.File, problem.Message}, "\t"))
+	}
+	w.Flush()
+	return errorCount
+}
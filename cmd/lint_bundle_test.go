@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	. "github.com/onsi/ginkgo/v2/dsl/core"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lint Bundle Cmd", func() {
+	When("Calling opcap lint bundle", func() {
+		It("should succeed on a valid bundle", func() {
+			out, err := executeCommand(lintBundleCmd(), "../internal/bundle/testdata/operators/acc-operator/21.10.19")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(ContainSubstring("No problems found"))
+		})
+		It("should fail on a broken bundle", func() {
+			out, err := executeCommand(lintBundleCmd(), "../internal/bundle/testdata/lint/broken-bundle")
+			Expect(err).To(MatchError(ContainSubstring("has 7 error(s)")))
+			Expect(out).To(ContainSubstring("no install modes declared"))
+		})
+		It("should require a directory", func() {
+			_, err := executeCommand(lintBundleCmd())
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -15,6 +15,7 @@ func listCmd() *cobra.Command {
 
 	cmd.AddCommand(listPackagesCmd())
 	cmd.AddCommand(listBundlesCmd())
+	cmd.AddCommand(listUpgradeGraphCmd())
 
 	return &cmd
 }
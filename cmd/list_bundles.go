@@ -2,26 +2,63 @@ package cmd
 
 import (
 	"context"
-	"fmt"
 	"io"
-	"os"
 	"strings"
-	"text/tabwriter"
 
 	"github.com/opdev/opcap/internal/bundle"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
-var listBundlesFlags struct {
+// bundlesSource are the flags selecting the directory or repository bundles are read from
+type bundlesSource struct {
 	bundlesDir  string
 	bundlesRepo string
+	gitRef      string
+	gitDepth    int
+	cacheDir    string
+}
+
+var listBundlesFlags struct {
+	bundlesSource
+	output string
+}
+
+func (s *bundlesSource) addFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&s.bundlesDir, "from-dir", "",
+		"specifies the source directory with bundles under the operators directory or a file-based catalog, can't be used with --from-repo")
+	flags.StringVar(&s.bundlesRepo, "from-repo", "https://github.com/redhat-openshift-ecosystem/certified-operators.git",
+		"Git repository URL or local path, a bare mirror for instance, from where to download bundles, can't be used with --from-dir")
+	flags.StringVar(&s.gitRef, "git-ref", "", "branch, tag or commit of the repository to read bundles from, the default branch when empty")
+	flags.IntVar(&s.gitDepth, "git-depth", 1, "number of commits fetched from the repository, 0 fetches the whole history")
+	flags.StringVar(&s.cacheDir, "cache-dir", "",
+		"directory where repositories are cloned and kept between runs, opcap/bundles in the user cache directory when empty")
+}
+
+// readBundles reads the bundles of the source directory or of the repository, checked out in the cache directory
+func (s *bundlesSource) readBundles() ([]bundle.Bundle, error) {
+	if s.bundlesDir != "" {
+		return bundle.ReadBundlesFromDir(s.bundlesDir)
+	}
+
+	dir, err := bundle.BundlesCacheDir(s.cacheDir, s.bundlesRepo)
+	if err != nil {
+		return nil, err
+	}
+	if err = bundle.GitCloneOrPullBundles(s.bundlesRepo, dir, bundle.GitOptions{Ref: s.gitRef, Depth: s.gitDepth}); err != nil {
+		return nil, err
+	}
+	return bundle.ReadBundlesFromDir(dir)
 }
 
 func listBundlesCmd() *cobra.Command {
 	cmd := cobra.Command{
 		Use:   "bundles",
 		Short: "List all bundles and versions",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return validateOutputFormat(listBundlesFlags.output, listOutputFormats...)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			err := listBundles(cmd.Context(), cmd.OutOrStdout())
 			if err != nil {
@@ -32,10 +69,8 @@ func listBundlesCmd() *cobra.Command {
 	}
 
 	flags := cmd.Flags()
-	flags.StringVar(&listBundlesFlags.bundlesDir, "from-dir", "",
-		"specifies the source directory with bundles under the operators directory, can't be used with --from-repo")
-	flags.StringVar(&listBundlesFlags.bundlesRepo, "from-repo", "https://github.com/redhat-openshift-ecosystem/certified-operators.git",
-		"Git repository URL from where to download bundles, can't be used with --from-dir")
+	listBundlesFlags.addFlags(flags)
+	flags.StringVar(&listBundlesFlags.output, "output", outputTable, "output format, one of "+strings.Join(listOutputFormats, ", "))
 
 	cmd.MarkFlagsMutuallyExclusive("from-dir", "from-repo")
 
@@ -43,40 +78,20 @@ func listBundlesCmd() *cobra.Command {
 }
 
 func listBundles(ctx context.Context, out io.Writer) error {
-	if listBundlesFlags.bundlesDir != "" {
-		bundles, err := bundle.ReadBundlesFromDir(listBundlesFlags.bundlesDir)
-		if err != nil {
-			return err
-		}
-		printBundles(bundles, out)
-		return nil
-	}
-
-	dir, err := os.MkdirTemp("", "bundles-*")
-	if err != nil {
-		return err
-	}
-	defer os.RemoveAll(dir)
-
-	if err = bundle.GitCloneOrPullBundles(listBundlesFlags.bundlesRepo, dir); err != nil {
-		return err
-	}
-
-	bundles, err := bundle.ReadBundlesFromDir(dir)
+	bundles, err := listBundlesFlags.readBundles()
 	if err != nil {
 		return err
 	}
-	printBundles(bundles, out)
-	return nil
+	return printBundles(bundles, listBundlesFlags.output, out)
 }
 
-func printBundles(bundles []bundle.Bundle, out io.Writer) {
-	headings := "Package Name\tStarting CSV\tVersion\tDefault Channel\tOcpVersions"
-	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, headings)
+func printBundles(bundles []bundle.Bundle, format string, out io.Writer) error {
+	list := listOutput{
+		columns: []string{"Package Name", "Starting CSV", "Version", "Default Channel", "OcpVersions"},
+		fields:  []string{"packageName", "startingCSV", "version", "defaultChannel", "ocpVersions"},
+	}
 	for _, bundle := range bundles {
-		packageInfo := []string{bundle.PackageName, bundle.StartingCSV, bundle.Channel, bundle.Version, bundle.OcpVersions}
-		fmt.Fprintln(w, strings.Join(packageInfo, "\t"))
+		list.rows = append(list.rows, []string{bundle.PackageName, bundle.StartingCSV, bundle.Version, bundle.Channel, bundle.OcpVersions})
 	}
-	w.Flush()
+	return printList(out, format, list)
 }
@@ -1,8 +1,17 @@
 package cmd
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	. "github.com/onsi/ginkgo/v2/dsl/core"
 	. "github.com/onsi/gomega"
+
+	"github.com/opdev/opcap/internal/bundle"
 )
 
 var _ = Describe("List Bundles Cmd", func() {
@@ -14,7 +23,7 @@ var _ = Describe("List Bundles Cmd", func() {
 		Context("repo flag", func() {
 			It("should succeed", func() {
 				gitUrl = "https://github.com/redhat-openshift-ecosystem/redhat-marketplace-operators.git"
-				_, err = executeCommand(listBundlesCmd(), []string{"--from-repo=" + gitUrl}...)
+				_, err = executeCommand(listBundlesCmd(), []string{"--from-repo=" + gitUrl, "--cache-dir=" + GinkgoT().TempDir()}...)
 				Expect(err).ToNot(HaveOccurred())
 			})
 		})
@@ -25,5 +34,85 @@ var _ = Describe("List Bundles Cmd", func() {
 				Expect(err).ToNot(HaveOccurred())
 			})
 		})
+		// test list bundles '--from-repo' flag with a local bare repository
+		Context("local repo flag", func() {
+			It("should list the bundles of the ref and keep the clone in the cache directory", func() {
+				sourceDir := GinkgoT().TempDir()
+				source, err := git.PlainInit(sourceDir, false)
+				Expect(err).ToNot(HaveOccurred())
+				catalog, err := os.ReadFile("../internal/bundle/testdata/fbc/widget-operator/catalog.yaml")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(os.WriteFile(filepath.Join(sourceDir, "catalog.yaml"), catalog, 0o644)).To(Succeed())
+				workTree, err := source.Worktree()
+				Expect(err).ToNot(HaveOccurred())
+				_, err = workTree.Add("catalog.yaml")
+				Expect(err).ToNot(HaveOccurred())
+				hash, err := workTree.Commit("catalog", &git.CommitOptions{
+					Author: &object.Signature{Name: "opcap", Email: "opcap@example.com", When: time.Now()},
+				})
+				Expect(err).ToNot(HaveOccurred())
+				_, err = source.CreateTag("v1", hash, nil)
+				Expect(err).ToNot(HaveOccurred())
+
+				bareRepo := filepath.Join(GinkgoT().TempDir(), "catalog.git")
+				_, err = git.PlainClone(bareRepo, true, &git.CloneOptions{URL: sourceDir})
+				Expect(err).ToNot(HaveOccurred())
+
+				cacheDir := GinkgoT().TempDir()
+				out, err := executeCommand(listBundlesCmd(), "--from-repo="+bareRepo, "--git-ref=v1", "--cache-dir="+cacheDir)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(out).To(ContainSubstring("widget-operator.v1.0.0"))
+				cloneDir, err := bundle.BundlesCacheDir(cacheDir, bareRepo)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(filepath.Join(cloneDir, "catalog.yaml")).To(BeARegularFile())
+			})
+		})
+		// test list bundles cmd '--from-dir' flag with a file-based catalog
+		Context("file-based catalog dir flag", func() {
+			It("should list the catalog bundles", func() {
+				out, err := executeCommand(listBundlesCmd(), []string{"--from-dir=../internal/bundle/testdata/fbc"}...)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(out).To(ContainSubstring("gadget-operator.v0.2.0"))
+				Expect(out).To(ContainSubstring("widget-operator.v1.0.0"))
+			})
+		})
+		// test list bundles cmd '--output' flag
+		Context("output flag", func() {
+			It("should print the version and default channel under their headings", func() {
+				out, err := executeCommand(listBundlesCmd(), "--from-dir=../internal/bundle/testdata/fbc")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(out).To(MatchRegexp(`Version\s+Default Channel`))
+				Expect(out).To(MatchRegexp(`widget-operator.v1.0.0\s+1.0.0\s+fast`))
+			})
+			It("should print JSON", func() {
+				out, err := executeCommand(listBundlesCmd(), "--from-dir=../internal/bundle/testdata/fbc", "--output=json")
+				Expect(err).ToNot(HaveOccurred())
+				var bundles []map[string]string
+				Expect(json.Unmarshal([]byte(out), &bundles)).To(Succeed())
+				Expect(bundles).To(HaveLen(3))
+				Expect(bundles[2]).To(Equal(map[string]string{
+					"packageName":    "widget-operator",
+					"startingCSV":    "widget-operator.v1.0.0",
+					"version":        "1.0.0",
+					"defaultChannel": "fast",
+					"ocpVersions":    "",
+				}))
+			})
+			It("should print YAML", func() {
+				out, err := executeCommand(listBundlesCmd(), "--from-dir=../internal/bundle/testdata/fbc", "--output=yaml")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(out).To(ContainSubstring("- defaultChannel: fast\n"))
+			})
+			It("should print CSV", func() {
+				out, err := executeCommand(listBundlesCmd(), "--from-dir=../internal/bundle/testdata/fbc", "--output=csv")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(out).To(HavePrefix("packageName,startingCSV,version,defaultChannel,ocpVersions\n"))
+				Expect(out).To(ContainSubstring("widget-operator,widget-operator.v1.0.0,1.0.0,fast,\n"))
+			})
+			It("should fail on an unknown format", func() {
+				_, err := executeCommand(listBundlesCmd(), "--from-dir=../internal/bundle/testdata/fbc", "--output=xml")
+				Expect(err).To(MatchError("invalid output format xml, expected one of table, json, yaml, csv"))
+			})
+		})
 	})
 })
@@ -2,10 +2,8 @@ package cmd
 
 import (
 	"context"
-	"fmt"
 	"io"
 	"strings"
-	"text/tabwriter"
 
 	"github.com/opdev/opcap/internal/packages"
 	pkgserverv1 "github.com/operator-framework/operator-lifecycle-manager/pkg/package-server/apis/operators/v1"
@@ -18,13 +16,17 @@ import (
 var packageListFlags struct {
 	CatalogSource string
 	Packages      []string
+	Output        string
 }
 
 func listPackagesCmd() *cobra.Command {
 	cmd := cobra.Command{
 		Use:   "packages",
 		Short: "List the package manifests for a given CatalogSource and Namespace",
-		RunE:  listPackagesRunE,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return validateOutputFormat(packageListFlags.Output, listOutputFormats...)
+		},
+		RunE: listPackagesRunE,
 	}
 
 	flags := cmd.Flags()
@@ -32,6 +34,7 @@ func listPackagesCmd() *cobra.Command {
 	flags.StringVar(&packageListFlags.CatalogSource, "catalogsource", "certified-operators",
 		"specifies the catalogsource to test against")
 	flags.StringSliceVar(&packageListFlags.Packages, "packages", []string{}, "a list of package(s) which limits audits and/or other flag(s) output")
+	flags.StringVar(&packageListFlags.Output, "output", outputTable, "output format, one of "+strings.Join(listOutputFormats, ", "))
 
 	return &cmd
 }
@@ -61,14 +64,13 @@ func listPackages(ctx context.Context, out io.Writer, c client.Client) error {
 		return err
 	}
 
-	headings := "Package Name\tCatalog Source\tCatalog Source Namespace"
-	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, headings)
+	list := listOutput{
+		columns: []string{"Package Name", "Catalog Source", "Catalog Source Namespace"},
+		fields:  []string{"name", "catalogSource", "catalogSourceNamespace"},
+	}
 	for _, packageManifest := range packageManifestList {
-		packageInfo := []string{packageManifest.Name, packageManifest.Status.CatalogSource, packageManifest.Status.CatalogSourceNamespace}
-		fmt.Fprintln(w, strings.Join(packageInfo, "\t"))
+		list.rows = append(list.rows, []string{packageManifest.Name, packageManifest.Status.CatalogSource, packageManifest.Status.CatalogSourceNamespace})
 	}
-	w.Flush()
 
-	return nil
+	return printList(out, packageListFlags.Output, list)
 }
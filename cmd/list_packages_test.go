@@ -53,6 +53,28 @@ var _ = Describe("ListPackage Cmd", func() {
 				Expect(err).ToNot(HaveOccurred())
 			})
 		})
+		When("an output format is given", func() {
+			It("should print the packages in that format", func() {
+				savedFlags := packageListFlags
+				DeferCleanup(func() { packageListFlags = savedFlags })
+				packageListFlags.CatalogSource = "certified-operators"
+				packageListFlags.Output = "csv"
+				packageManifest := &pkgserverv1.PackageManifest{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test",
+						Namespace: "test-catalogsourcenamespace",
+					},
+					Status: pkgserverv1.PackageManifestStatus{
+						CatalogSource:          "certified-operators",
+						CatalogSourceNamespace: "test-catalogsourcenamespace",
+					},
+				}
+				fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(packageManifest).Build()
+				out := bytes.NewBufferString("")
+				Expect(listPackages(context.TODO(), out, fakeClient)).To(Succeed())
+				Expect(out.String()).To(Equal("name,catalogSource,catalogSourceNamespace\ntest,certified-operators,test-catalogsourcenamespace\n"))
+			})
+		})
 		When("the list fails", func() {
 			It("should error", func() {
 				builder := fake.NewClientBuilder()
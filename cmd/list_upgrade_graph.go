@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/opdev/opcap/internal/bundle"
+
+	"github.com/spf13/cobra"
+)
+
+// outputDOT prints the upgrade graphs in the Graphviz DOT language
+const outputDOT = "dot"
+
+var listUpgradeGraphFlags struct {
+	bundlesSource
+	packageName string
+	channel     string
+	output      string
+}
+
+func listUpgradeGraphCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "upgrade-graph",
+		Short: "List the upgrade graph of a package per channel",
+		Long: "Builds the upgrade graph of every channel of a package from the replaces, skips and olm.skipRange " +
+			"of its bundles and reports dead ends, unreachable versions, cycles and versions that can't upgrade to the channel head",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return validateOutputFormat(listUpgradeGraphFlags.output, append([]string{outputDOT}, listOutputFormats...)...)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listUpgradeGraph(cmd.Context(), cmd.OutOrStdout())
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&listUpgradeGraphFlags.packageName, "package", "", "name of the package to build the upgrade graph for")
+	flags.StringVar(&listUpgradeGraphFlags.channel, "channel", "", "only build the upgrade graph of this channel")
+	flags.StringVar(&listUpgradeGraphFlags.output, "output", outputDOT,
+		"output format, dot or one of "+strings.Join(listOutputFormats, ", ")+". The table and csv formats summarize the problems of every channel")
+	listUpgradeGraphFlags.addFlags(flags)
+
+	cmd.MarkFlagsMutuallyExclusive("from-dir", "from-repo")
+	_ = cmd.MarkFlagRequired("package")
+
+	return &cmd
+}
+
+func listUpgradeGraph(ctx context.Context, out io.Writer) error {
+	bundles, err := listUpgradeGraphFlags.readBundles()
+	if err != nil {
+		return err
+	}
+
+	graphs := []bundle.UpgradeGraph{}
+	for _, graph := range bundle.BuildUpgradeGraphs(bundles, listUpgradeGraphFlags.packageName) {
+		if listUpgradeGraphFlags.channel == "" || graph.Channel == listUpgradeGraphFlags.channel {
+			graphs = append(graphs, graph)
+		}
+	}
+	if len(graphs) == 0 {
+		return fmt.Errorf("no bundles found for package %s", listUpgradeGraphFlags.packageName)
+	}
+
+	switch listUpgradeGraphFlags.output {
+	case outputDOT:
+		for _, graph := range graphs {
+			fmt.Fprint(out, graph.DOT())
+		}
+		return nil
+	case outputJSON, outputYAML:
+		return printStructured(out, listUpgradeGraphFlags.output, graphs)
+	}
+
+	list := listOutput{
+		columns: []string{"Package Name", "Channel", "Head", "Dead Ends", "Unreachable", "Cycles", "Cannot Reach Head", "Missing References"},
+		fields:  []string{"packageName", "channel", "head", "deadEnds", "unreachable", "cycles", "cannotReachHead", "missingReferences"},
+	}
+	for _, graph := range graphs {
+		cycles := []string{}
+		for _, cycle := range graph.Cycles {
+			cycles = append(cycles, strings.Join(cycle, " "))
+		}
+		list.rows = append(list.rows, []string{
+			graph.Package,
+			graph.Channel,
+			graph.Head,
+			strings.Join(graph.DeadEnds, ","),
+			strings.Join(graph.Unreachable, ","),
+			strings.Join(cycles, ","),
+			strings.Join(graph.CannotReachHead, ","),
+			strings.Join(graph.MissingReferences, ","),
+		})
+	}
+	return printList(out, listUpgradeGraphFlags.output, list)
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2/dsl/core"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("List Upgrade Graph Cmd", func() {
+	const bundlesDir = "--from-dir=../internal/bundle/testdata/upgrade-graph"
+
+	When("Calling opcap list upgrade-graph", func() {
+		It("should print the graph of every channel in DOT", func() {
+			out, err := executeCommand(listUpgradeGraphCmd(), bundlesDir, "--package=widget-operator")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(ContainSubstring(`digraph "widget-operator/fast" {`))
+			Expect(out).To(ContainSubstring(`digraph "widget-operator/stable" {`))
+		})
+
+		It("should print the graph of a channel in JSON", func() {
+			out, err := executeCommand(listUpgradeGraphCmd(), bundlesDir, "--package=widget-operator", "--channel=fast", "--output=json")
+			Expect(err).ToNot(HaveOccurred())
+			var graphs []map[string]interface{}
+			Expect(json.Unmarshal([]byte(out), &graphs)).To(Succeed())
+			Expect(graphs).To(HaveLen(1))
+			Expect(graphs[0]["head"]).To(Equal("widget-operator.v2.3.0"))
+			Expect(graphs[0]["deadEnds"]).To(Equal([]interface{}{"widget-operator.v2.0.1"}))
+		})
+
+		It("should summarize the problems of every channel as CSV", func() {
+			out, err := executeCommand(listUpgradeGraphCmd(), bundlesDir, "--package=widget-operator", "--output=csv")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(HavePrefix("packageName,channel,head,deadEnds,unreachable,cycles,cannotReachHead,missingReferences\n"))
+			Expect(out).To(ContainSubstring("widget-operator,fast,widget-operator.v2.3.0,widget-operator.v2.0.1,"))
+			Expect(out).To(ContainSubstring("widget-operator,stable,widget-operator.v2.0.0,,,,,\n"))
+		})
+
+		It("should fail on an unknown package", func() {
+			_, err := executeCommand(listUpgradeGraphCmd(), bundlesDir, "--package=unknown-operator")
+			Expect(err).To(MatchError("no bundles found for package unknown-operator"))
+		})
+
+		It("should fail on an unknown output format", func() {
+			_, err := executeCommand(listUpgradeGraphCmd(), bundlesDir, "--package=widget-operator", "--output=xml")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
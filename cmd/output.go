@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Output formats of the list commands
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+	outputCSV   = "csv"
+)
+
+var listOutputFormats = []string{outputTable, outputJSON, outputYAML, outputCSV}
+
+// listOutput is what a list command prints. Every row has a value per column, columns being the table headings
+// and fields the names of the values in the JSON, YAML and CSV outputs. Fields must not change once released,
+// scripts rely on them.
+type listOutput struct {
+	columns []string
+	fields  []string
+	rows    [][]string
+}
+
+// validateOutputFormat fails when format isn't one of the formats a command supports
+func validateOutputFormat(format string, formats ...string) error {
+	for _, f := range formats {
+		if format == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid output format %s, expected one of %s", format, strings.Join(formats, ", "))
+}
+
+// printList prints the output of a list command in a format, a table when the format is empty
+func printList(out io.Writer, format string, list listOutput) error {
+	switch format {
+	case outputJSON, outputYAML:
+		objs := make([]map[string]string, 0, len(list.rows))
+		for _, row := range list.rows {
+			obj := map[string]string{}
+			for i, field := range list.fields {
+				obj[field] = row[i]
+			}
+			objs = append(objs, obj)
+		}
+		return printStructured(out, format, objs)
+	case outputCSV:
+		w := csv.NewWriter(out)
+		if err := w.Write(list.fields); err != nil {
+			return err
+		}
+		if err := w.WriteAll(list.rows); err != nil {
+			return err
+		}
+		return w.Error()
+	case outputTable, "":
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(list.columns, "\t"))
+		for _, row := range list.rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		return w.Flush()
+	}
+	return validateOutputFormat(format, listOutputFormats...)
+}
+
+// printStructured prints a value as indented JSON or as YAML, using its JSON field names
+func printStructured(out io.Writer, format string, value interface{}) error {
+	if format == outputYAML {
+		data, err := yaml.Marshal(value)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(data)
+		return err
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
@@ -32,6 +32,8 @@ func rootCmd() *cobra.Command {
 	cmd.AddCommand(versionCmd())
 	cmd.AddCommand(checkCmd())
 	cmd.AddCommand(listCmd())
+	cmd.AddCommand(lintCmd())
+	cmd.AddCommand(diffCmd())
 
 	return &cmd
 }
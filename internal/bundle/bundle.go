@@ -1,44 +1,39 @@
 package bundle
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
 
 	operatorv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/yaml"
-
-	git "github.com/go-git/go-git/v5"
 )
 
-func GitCloneOrPullBundles(URL string, outputDir string) error {
-	// Clone bundle repository, main branch only (should be the default for certified-operators)
-	if _, err := git.PlainClone(outputDir, false, &git.CloneOptions{
-		URL:      URL,
-		Progress: os.Stdout,
-	}); err != nil && err != git.ErrRepositoryAlreadyExists {
-		return fmt.Errorf("failed cloning repository: %s", err)
-	}
-	// Pull data if repo already exists
-	repo, err := git.PlainOpen(outputDir)
-	if err != nil {
-		return fmt.Errorf("failed opening existent repository: %s", err)
-	}
-	workTree, err := repo.Worktree()
-	if err != nil {
-		return err
-	}
-	err = workTree.Pull(&git.PullOptions{RemoteName: "origin"})
-	if err != nil && err != git.NoErrAlreadyUpToDate {
-		return fmt.Errorf("failed pulling data from repository: %s", err)
-	}
-	return nil
-}
+// annotationsPath is where the bundle metadata is stored, relative to the bundle directory
+var annotationsPath = filepath.Join("metadata", "annotations.yaml")
 
+// ReadBundlesFromDir reads the bundles of a directory, either stored as operators/<package>/<version> like the
+// certified-operators repository or as a file-based catalog when there is no operators directory
 func ReadBundlesFromDir(bundlesDir string) ([]Bundle, error) {
 	bundles := []Bundle{}
 
+	if _, err := os.Stat(filepath.Join(bundlesDir, "operators")); errors.Is(err, fs.ErrNotExist) {
+		if info, err := os.Stat(bundlesDir); err == nil && info.IsDir() {
+			bundles, err := ReadBundlesFromFBC(bundlesDir)
+			if err != nil {
+				return nil, fmt.Errorf("%s has no operators directory and can't be read as a file-based catalog: %s", bundlesDir, err)
+			}
+			return bundles, nil
+		}
+	}
+
 	operators, err := os.ReadDir(filepath.Join(bundlesDir, "operators"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract operators from repo: %s", err)
@@ -62,70 +57,210 @@ func ReadBundlesFromDir(bundlesDir string) ([]Bundle, error) {
 			if !version.IsDir() {
 				continue
 			}
-			bundle := Bundle{}
-			bundle.Version = version.Name()
-			versionDir := filepath.Join(path, version.Name())
 
-			data, err := os.ReadDir(versionDir)
+			bundle, err := ReadBundle(filepath.Join(path, version.Name()))
 			if err != nil {
 				return nil, fmt.Errorf("failed to extract bundle for version: %s", err)
 			}
 
-			for _, d := range data {
+			// a bundle can't be listed without its annotations or CSV, other manifests don't matter
+			for _, fileErr := range bundle.Errors {
+				if (fileErr.File == annotationsPath && !errors.Is(fileErr, fs.ErrNotExist)) || strings.Contains(fileErr.File, "clusterserviceversion") {
+					return nil, fmt.Errorf("failed to read bundle %s: %s", bundle.Path, fileErr)
+				}
+			}
+
+			bundles = append(bundles, bundle)
+		}
+	}
+	return bundles, nil
+}
 
-				// checking the manifests folder for the csv file
-				if d.IsDir() && d.Name() == "manifests" {
+// ReadBundle parses a bundle directory holding the manifests and metadata directories. Files that can't be read
+// or decoded don't fail the parsing, they are listed in the Errors of the bundle. An error is only returned when
+// the bundle directory can't be read.
+func ReadBundle(bundleDir string) (Bundle, error) {
+	if _, err := os.ReadDir(bundleDir); err != nil {
+		return Bundle{}, err
+	}
 
-					manifestPath := filepath.Join(versionDir, "manifests")
+	bundle := Bundle{
+		Version: filepath.Base(bundleDir),
+		Path:    bundleDir,
+	}
+	bundle.readAnnotations()
+	bundle.readManifests()
 
-					if err := filepath.Walk(manifestPath, func(path string, f os.FileInfo, err error) error {
-						if err == nil && strings.Contains(f.Name(), "clusterserviceversion") {
-							bundle.StartingCSV, err = getStartingCsv(filepath.Join(manifestPath, f.Name()))
-							if err != nil {
-								return fmt.Errorf("failed to get StartingCSV for bundle: %s", err)
-							}
-						}
+	return bundle, nil
+}
 
-						return nil
-					}); err != nil {
-						return nil, err
-					}
-				}
+func (b *Bundle) readAnnotations() {
+	annotations, err := getAnnotations(filepath.Join(b.Path, annotationsPath))
+	if err != nil {
+		b.Errors = append(b.Errors, FileError{File: annotationsPath, Err: fmt.Errorf("could not read annotations: %w", err)})
+		return
+	}
 
-				// Checking the metadata folder for the annotations file
-				if d.IsDir() && d.Name() == "metadata" {
-					annotationsPath := filepath.Join(versionDir, "metadata", "annotations.yaml")
-					annotations, err := getAnnotations(annotationsPath)
-					if err != nil {
-						return nil, fmt.Errorf("failed to get metadata annotations for bundle: %s", err)
-					}
-
-					bundle.PackageName = annotations["operators.operatorframework.io.bundle.package.v1"]
-					bundle.Channel = annotations["operators.operatorframework.io.bundle.channel.default.v1"]
-					bundle.OcpVersions = annotations["com.redhat.openshift.versions"]
-				}
-			}
-			bundles = append(bundles, bundle)
+	b.Annotations = annotations
+	b.PackageName = annotations[packageAnnotation]
+	b.Channel = annotations[defaultChannelAnnotation]
+	b.OcpVersions = annotations[ocpVersionsAnnotation]
+	for _, channel := range strings.Split(annotations[channelsAnnotation], ",") {
+		if channel = strings.TrimSpace(channel); channel != "" {
+			b.Channels = append(b.Channels, channel)
+		}
+	}
+}
+
+func (b *Bundle) readManifests() {
+	entries, err := os.ReadDir(filepath.Join(b.Path, "manifests"))
+	if err != nil {
+		b.Errors = append(b.Errors, FileError{File: "manifests", Err: fmt.Errorf("could not read manifests directory: %w", err)})
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		file := filepath.Join("manifests", entry.Name())
+		objs, err := decodeManifests(filepath.Join(b.Path, file))
+		if err != nil {
+			b.Errors = append(b.Errors, FileError{File: file, Err: fmt.Errorf("could not decode manifest: %w", err)})
+		}
+
+		for _, obj := range objs {
+			b.addManifest(file, obj)
 		}
 	}
-	return bundles, nil
 }
 
-func getStartingCsv(filePath string) (string, error) {
-	fileReader, err := os.Open(filePath)
+// addManifest adds an object of the bundle, setting the CSV and CRDs from it
+func (b *Bundle) addManifest(file string, obj unstructured.Unstructured) {
+	b.Manifests = append(b.Manifests, Manifest{
+		File:       file,
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Name:       obj.GetName(),
+	})
+
+	switch obj.GetKind() {
+	case "ClusterServiceVersion":
+		csv := &operatorv1alpha1.ClusterServiceVersion{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, csv); err != nil {
+			b.Errors = append(b.Errors, FileError{File: file, Err: fmt.Errorf("invalid ClusterServiceVersion: %w", err)})
+			return
+		}
+		// a bundle holds a single CSV, the first one found is kept
+		if b.CSV == nil {
+			b.setCSV(csv)
+		}
+	case "CustomResourceDefinition":
+		b.CRDs = append(b.CRDs, crdFromManifest(file, obj))
+	}
+}
+
+func (b *Bundle) setCSV(csv *operatorv1alpha1.ClusterServiceVersion) {
+	b.CSV = csv
+	b.StartingCSV = csv.Name
+	b.OwnedCRDs = csv.Spec.CustomResourceDefinitions.Owned
+	b.RequiredCRDs = csv.Spec.CustomResourceDefinitions.Required
+	b.InstallModes = csv.Spec.InstallModes
+	b.RelatedImages = csv.Spec.RelatedImages
+	b.Replaces = csv.Spec.Replaces
+	b.Skips = csv.Spec.Skips
+	b.SkipRange = csv.Annotations["olm.skipRange"]
+	b.MinKubeVersion = csv.Spec.MinKubeVersion
+}
+
+// decodeManifests decodes every document of a YAML or JSON file
+func decodeManifests(path string) ([]unstructured.Unstructured, error) {
+	file, err := os.Open(path)
 	if err != nil {
-		return "", fmt.Errorf("unable to load file %s: %s", filePath, err)
+		return nil, err
+	}
+	defer file.Close()
+
+	objs := []unstructured.Unstructured{}
+	decoder := yaml.NewYAMLOrJSONDecoder(file, 4096)
+	for {
+		obj := unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objs, nil
+			}
+			return objs, err
+		}
+		if obj.Object != nil {
+			objs = append(objs, obj)
+		}
 	}
-	defer fileReader.Close()
+}
 
-	decoder := yaml.NewYAMLOrJSONDecoder(fileReader, 30)
-	csv := operatorv1alpha1.ClusterServiceVersion{}
+// crdFromManifest reads the group, kind, versions and schemas of an apiextensions.k8s.io/v1 or v1beta1 CRD
+func crdFromManifest(file string, obj unstructured.Unstructured) CRD {
+	crd := CRD{File: file, Name: obj.GetName(), Schemas: map[string]*apiextensionsv1.JSONSchemaProps{}}
+	crd.Group, _, _ = unstructured.NestedString(obj.Object, "spec", "group")
+	crd.Kind, _, _ = unstructured.NestedString(obj.Object, "spec", "names", "kind")
+	// v1beta1 CRDs can declare a single version, both served and stored
+	if version, ok, _ := unstructured.NestedString(obj.Object, "spec", "version"); ok {
+		crd.Versions = append(crd.Versions, version)
+		crd.Served = append(crd.Served, version)
+		crd.Storage = version
+	}
+	versions, _, _ := unstructured.NestedSlice(obj.Object, "spec", "versions")
+	for _, v := range versions {
+		if version, ok := v.(map[string]interface{}); ok {
+			name, ok := version["name"].(string)
+			if !ok {
+				continue
+			}
+			if !contains(crd.Versions, name) {
+				crd.Versions = append(crd.Versions, name)
+			}
+			if served, _ := version["served"].(bool); served && !contains(crd.Served, name) {
+				crd.Served = append(crd.Served, name)
+			}
+			if storage, _ := version["storage"].(bool); storage {
+				crd.Storage = name
+			}
+			if schema := schemaFromManifest(version, "schema", "openAPIV3Schema"); schema != nil {
+				crd.Schemas[name] = schema
+			}
+		}
+	}
 
-	if err = decoder.Decode(&csv); err != nil {
-		return "", err
+	// v1beta1 CRDs can validate every version with the same schema
+	if schema := schemaFromManifest(obj.Object, "spec", "validation", "openAPIV3Schema"); schema != nil {
+		for _, version := range crd.Versions {
+			if crd.Schemas[version] == nil {
+				crd.Schemas[version] = schema
+			}
+		}
 	}
+	return crd
+}
 
-	return csv.Name, nil
+// schemaFromManifest converts the OpenAPI v3 schema at fields, nil when there is none or it is invalid
+func schemaFromManifest(obj map[string]interface{}, fields ...string) *apiextensionsv1.JSONSchemaProps {
+	value, ok, _ := unstructured.NestedMap(obj, fields...)
+	if !ok {
+		return nil
+	}
+	schema := &apiextensionsv1.JSONSchemaProps{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(value, schema); err != nil {
+		return nil
+	}
+	return schema
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
 
 func getAnnotations(filePath string) (map[string]string, error) {
@@ -17,7 +17,7 @@ var _ = Describe("Package Bundle", func() {
 			Expect(err).ToNot(HaveOccurred())
 			defer os.RemoveAll(dir)
 
-			err = GitCloneOrPullBundles(URL, dir)
+			err = GitCloneOrPullBundles(URL, dir, GitOptions{Depth: 1})
 			Expect(err).ToNot(HaveOccurred())
 		})
 	})
@@ -31,6 +31,7 @@ var _ = Describe("Package Bundle", func() {
 				Version:     "21.10.19",
 				StartingCSV: "acc-operator.v21.10.19",
 				OcpVersions: "v4.6-v4.8",
+				Path:        "testdata/operators/acc-operator/21.10.19",
 			},
 			{
 				PackageName: "acc-operator",
@@ -38,6 +39,7 @@ var _ = Describe("Package Bundle", func() {
 				Version:     "21.10.7",
 				StartingCSV: "acc-operator.v21.10.7",
 				OcpVersions: "v4.6-v4.8",
+				Path:        "testdata/operators/acc-operator/21.10.7",
 			},
 			{
 				PackageName: "acc-operator",
@@ -45,19 +47,72 @@ var _ = Describe("Package Bundle", func() {
 				Version:     "21.12.60",
 				StartingCSV: "acc-operator.v21.12.60",
 				OcpVersions: "v4.6-v4.8",
+				Path:        "testdata/operators/acc-operator/21.12.60",
 			},
 		}
 		It("should succeed", func() {
 			bundles, err := ReadBundlesFromDir(repoPath)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(bundles).To(ContainElements(testData))
+
+			listed := []Bundle{}
+			for _, b := range bundles {
+				listed = append(listed, Bundle{
+					PackageName: b.PackageName,
+					Channel:     b.Channel,
+					Version:     b.Version,
+					StartingCSV: b.StartingCSV,
+					OcpVersions: b.OcpVersions,
+					Path:        b.Path,
+				})
+			}
+			Expect(listed).To(ContainElements(testData))
 		})
 	})
-	When("running getStartingCSV", func() {
-		It("should succeed", func() {
-			startingCSV, err := getStartingCsv("testdata/operators/acc-operator/21.10.7/manifests/acc-operator.clusterserviceversion.yaml")
+	When("reading a single bundle", func() {
+		It("should parse the CSV, CRDs and annotations", func() {
+			b, err := ReadBundle("testdata/operators/acc-operator/21.10.7")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(b.Errors).To(BeEmpty())
+
+			Expect(b.StartingCSV).To(Equal("acc-operator.v21.10.7"))
+			Expect(b.CSV).ToNot(BeNil())
+			Expect(b.PackageName).To(Equal("acc-operator"))
+			Expect(b.Channels).To(Equal([]string{"alpha"}))
+			Expect(b.Annotations).To(HaveKeyWithValue("operators.operatorframework.io.bundle.mediatype.v1", "registry+v1"))
+
+			Expect(b.OwnedCRDs).To(HaveLen(1))
+			Expect(b.OwnedCRDs[0].Name).To(Equal("astracontrolcenters.astra.netapp.io"))
+			Expect(b.CRDs).To(HaveLen(1))
+			Expect(b.CRDs[0].File).To(Equal("manifests/astra.netapp.io_astracontrolcenters.yaml"))
+			Expect(b.CRDs[0].Group).To(Equal("astra.netapp.io"))
+			Expect(b.CRDs[0].Kind).To(Equal("AstraControlCenter"))
+			Expect(b.CRDs[0].Versions).To(ContainElement("v1"))
+
+			Expect(b.InstallModes).To(HaveLen(4))
+			Expect(b.MinKubeVersion).To(Equal("1.19.0"))
+			Expect(b.RelatedImages).ToNot(BeEmpty())
+			Expect(b.Manifests).To(ContainElement(Manifest{
+				File:       "manifests/acc-operator.clusterserviceversion.yaml",
+				APIVersion: "operators.coreos.com/v1alpha1",
+				Kind:       "ClusterServiceVersion",
+				Name:       "acc-operator.v21.10.7",
+			}))
+		})
+
+		It("should keep going on files that can't be decoded", func() {
+			b, err := ReadBundle("testdata/lint/broken-bundle")
 			Expect(err).ToNot(HaveOccurred())
-			Expect(startingCSV).To(Equal("acc-operator.v21.10.7"))
+			Expect(b.CSV).ToNot(BeNil())
+			Expect(b.Errors).To(BeEmpty())
+			Expect(b.Manifests).To(ContainElement(Manifest{File: "manifests/extra.yaml", Name: "no-kind"}))
+			Expect(b.Replaces).To(Equal("broken-operator.v0.0.9"))
+			Expect(b.Skips).To(Equal([]string{"broken-operator.v0.0.8"}))
+			Expect(b.SkipRange).To(Equal(">=0.0.1 <0.0.9"))
+		})
+
+		It("should fail on a missing directory", func() {
+			_, err := ReadBundle("testdata/operators/acc-operator/0.0.0")
+			Expect(err).To(HaveOccurred())
 		})
 	})
 	When("running getAnnotatinons", func() {
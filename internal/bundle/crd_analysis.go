@@ -0,0 +1,218 @@
+package bundle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// Upgrade risks of CRD changes, from the lowest to the highest
+const (
+	RiskNone   = "none"
+	RiskLow    = "low"
+	RiskMedium = "medium"
+	RiskHigh   = "high"
+)
+
+var riskLevels = map[string]int{RiskNone: 0, RiskLow: 1, RiskMedium: 2, RiskHigh: 3}
+
+// CRDRisk is the upgrade risk of a CRD, the highest risk of its changes
+type CRDRisk struct {
+	Name    string
+	Risk    string
+	Changes []CRDChange
+}
+
+// CRDChange is a change of a CRD that can break its existing objects or clients
+type CRDChange struct {
+	// Version is empty when the whole CRD is removed
+	Version string
+	Change  string
+	Detail  string
+	Risk    string
+}
+
+// Breaking tells whether the change can break existing objects or clients
+func (c CRDChange) Breaking() bool {
+	return riskLevels[c.Risk] >= riskLevels[RiskMedium]
+}
+
+// AnalyzeCRDs compares the CRDs of two bundles and returns the upgrade risk of every CRD of the first one. It finds
+// removed CRDs, removed versions that were served or stored, versions no longer served, storage version changes and,
+// in the schemas of the versions both bundles have, removed properties, new required fields, type changes, narrowed
+// enums and tightened validation: stricter bounds, lengths and item counts, new or changed patterns.
+func AnalyzeCRDs(from, to []CRD) []CRDRisk {
+	toCRDs := map[string]CRD{}
+	for _, crd := range to {
+		toCRDs[crd.Name] = crd
+	}
+
+	risks := []CRDRisk{}
+	for _, fromCRD := range from {
+		risk := CRDRisk{Name: fromCRD.Name, Risk: RiskNone, Changes: []CRDChange{}}
+		toCRD, ok := toCRDs[fromCRD.Name]
+		if ok {
+			risk.Changes = analyzeCRD(fromCRD, toCRD)
+		} else {
+			risk.Changes = append(risk.Changes, CRDChange{Change: Removed, Detail: "CRD removed", Risk: RiskHigh})
+		}
+		for _, change := range risk.Changes {
+			if riskLevels[change.Risk] > riskLevels[risk.Risk] {
+				risk.Risk = change.Risk
+			}
+		}
+		risks = append(risks, risk)
+	}
+
+	sort.Slice(risks, func(i, j int) bool { return risks[i].Name < risks[j].Name })
+	return risks
+}
+
+func analyzeCRD(from, to CRD) []CRDChange {
+	changes := []CRDChange{}
+	for _, version := range from.Versions {
+		served := contains(from.Served, version)
+		switch {
+		case !contains(to.Versions, version) && version == from.Storage:
+			changes = append(changes, CRDChange{Version: version, Change: Removed, Detail: "storage version removed", Risk: RiskHigh})
+		case !contains(to.Versions, version) && served:
+			changes = append(changes, CRDChange{Version: version, Change: Removed, Detail: "served version removed", Risk: RiskHigh})
+		case !contains(to.Versions, version):
+			changes = append(changes, CRDChange{Version: version, Change: Removed, Detail: "version removed", Risk: RiskLow})
+		case served && !contains(to.Served, version):
+			changes = append(changes, CRDChange{Version: version, Change: Changed, Detail: "version no longer served", Risk: RiskMedium})
+		}
+	}
+
+	if from.Storage != "" && to.Storage != "" && from.Storage != to.Storage {
+		changes = append(changes, CRDChange{
+			Version: to.Storage,
+			Change:  Changed,
+			Detail:  fmt.Sprintf("storage version changed from %s, stored objects need to be migrated", from.Storage),
+			Risk:    RiskLow,
+		})
+	}
+
+	for _, version := range from.Versions {
+		if !contains(to.Versions, version) {
+			continue
+		}
+		schemaChanges := []CRDChange{}
+		compareSchemas(from.Schemas[version], to.Schemas[version], "", func(change, detail, risk string) {
+			schemaChanges = append(schemaChanges, CRDChange{Version: version, Change: change, Detail: detail, Risk: risk})
+		})
+		sort.SliceStable(schemaChanges, func(i, j int) bool { return schemaChanges[i].Detail < schemaChanges[j].Detail })
+		changes = append(changes, schemaChanges...)
+	}
+	return changes
+}
+
+// compareSchemas walks two schemas of a CRD version together and reports the changes that can break existing
+// objects. Properties of a removed property aren't reported, only the removed property is.
+func compareSchemas(from, to *apiextensionsv1.JSONSchemaProps, path string, report func(change, detail, risk string)) {
+	if from == nil || to == nil {
+		return
+	}
+	field := path
+	if field == "" {
+		field = "."
+	}
+
+	if from.Type != "" && to.Type != "" && from.Type != to.Type {
+		report(Changed, fmt.Sprintf("field %s type changed from %s to %s", field, from.Type, to.Type), RiskHigh)
+		return
+	}
+
+	for _, required := range to.Required {
+		if !contains(from.Required, required) {
+			report(Changed, fmt.Sprintf("field %s.%s is now required", path, required), RiskHigh)
+		}
+	}
+
+	if len(to.Enum) > 0 {
+		toValues := enumValues(to.Enum)
+		if len(from.Enum) == 0 {
+			report(Changed, fmt.Sprintf("field %s is now restricted to %s", field, strings.Join(toValues, ", ")), RiskMedium)
+		}
+		for _, value := range enumValues(from.Enum) {
+			if !contains(toValues, value) {
+				report(Changed, fmt.Sprintf("field %s no longer accepts %s", field, value), RiskMedium)
+			}
+		}
+	}
+
+	bounds := []struct {
+		name     string
+		from, to *float64
+		upper    bool
+	}{
+		{"maximum", from.Maximum, to.Maximum, true},
+		{"minimum", from.Minimum, to.Minimum, false},
+		{"maxLength", toFloat(from.MaxLength), toFloat(to.MaxLength), true},
+		{"minLength", orZero(toFloat(from.MinLength)), toFloat(to.MinLength), false},
+		{"maxItems", toFloat(from.MaxItems), toFloat(to.MaxItems), true},
+		{"minItems", orZero(toFloat(from.MinItems)), toFloat(to.MinItems), false},
+	}
+	for _, bound := range bounds {
+		switch {
+		case bound.to == nil:
+		case bound.from == nil:
+			report(Changed, fmt.Sprintf("field %s now has a %s of %v", field, bound.name, *bound.to), RiskMedium)
+		case bound.upper && *bound.to < *bound.from, !bound.upper && *bound.to > *bound.from:
+			report(Changed, fmt.Sprintf("field %s %s changed from %v to %v", field, bound.name, *bound.from, *bound.to), RiskMedium)
+		}
+	}
+	if to.ExclusiveMaximum && !from.ExclusiveMaximum {
+		report(Changed, fmt.Sprintf("field %s maximum is now exclusive", field), RiskMedium)
+	}
+	if to.ExclusiveMinimum && !from.ExclusiveMinimum {
+		report(Changed, fmt.Sprintf("field %s minimum is now exclusive", field), RiskMedium)
+	}
+	switch {
+	case to.Pattern == "" || to.Pattern == from.Pattern:
+	case from.Pattern == "":
+		report(Changed, fmt.Sprintf("field %s now has to match %s", field, to.Pattern), RiskMedium)
+	default:
+		report(Changed, fmt.Sprintf("field %s pattern changed from %s to %s", field, from.Pattern, to.Pattern), RiskMedium)
+	}
+
+	for name, fromProperty := range from.Properties {
+		fromProperty := fromProperty
+		toProperty, ok := to.Properties[name]
+		if !ok {
+			report(Removed, fmt.Sprintf("field %s.%s removed", path, name), RiskMedium)
+			continue
+		}
+		compareSchemas(&fromProperty, &toProperty, path+"."+name, report)
+	}
+
+	if from.Items != nil && to.Items != nil {
+		compareSchemas(from.Items.Schema, to.Items.Schema, path+"[]", report)
+	}
+}
+
+func toFloat(value *int64) *float64 {
+	if value == nil {
+		return nil
+	}
+	f := float64(*value)
+	return &f
+}
+
+// orZero defaults a minimum length or item count to 0, which is what an unset one means
+func orZero(value *float64) *float64 {
+	if value == nil {
+		return new(float64)
+	}
+	return value
+}
+
+func enumValues(enum []apiextensionsv1.JSON) []string {
+	values := []string{}
+	for _, value := range enum {
+		values = append(values, string(value.Raw))
+	}
+	return values
+}
@@ -0,0 +1,166 @@
+package bundle
+
+import (
+	. "github.com/onsi/ginkgo/v2/dsl/core"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var _ = Describe("CRD analysis", func() {
+	enum := func(values ...string) []apiextensionsv1.JSON {
+		enum := []apiextensionsv1.JSON{}
+		for _, value := range values {
+			enum = append(enum, apiextensionsv1.JSON{Raw: []byte(`"` + value + `"`)})
+		}
+		return enum
+	}
+	specSchema := func(spec apiextensionsv1.JSONSchemaProps) *apiextensionsv1.JSONSchemaProps {
+		return &apiextensionsv1.JSONSchemaProps{
+			Type:       "object",
+			Properties: map[string]apiextensionsv1.JSONSchemaProps{"spec": spec},
+		}
+	}
+	crd := func(storage string, served []string, schemas map[string]*apiextensionsv1.JSONSchemaProps) CRD {
+		versions := []string{}
+		for version := range schemas {
+			versions = append(versions, version)
+		}
+		return CRD{Name: "widgets.example.com", Versions: versions, Served: served, Storage: storage, Schemas: schemas}
+	}
+
+	When("the schema of a version changes", func() {
+		It("should find removed properties, new required fields, type changes and narrowed enums", func() {
+			from := crd("v1", []string{"v1"}, map[string]*apiextensionsv1.JSONSchemaProps{
+				"v1": specSchema(apiextensionsv1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]apiextensionsv1.JSONSchemaProps{
+						"color":    {Type: "string", Enum: enum("blue", "red")},
+						"size":     {Type: "integer"},
+						"replicas": {Type: "integer"},
+						"mode":     {Type: "string"},
+						"labels": {Type: "object", Properties: map[string]apiextensionsv1.JSONSchemaProps{
+							"team": {Type: "string"},
+						}},
+					},
+				}),
+			})
+			to := crd("v1", []string{"v1"}, map[string]*apiextensionsv1.JSONSchemaProps{
+				"v1": specSchema(apiextensionsv1.JSONSchemaProps{
+					Type:     "object",
+					Required: []string{"size"},
+					Properties: map[string]apiextensionsv1.JSONSchemaProps{
+						"color":    {Type: "string", Enum: enum("blue")},
+						"size":     {Type: "integer"},
+						"replicas": {Type: "string"},
+						"mode":     {Type: "string", Enum: enum("fast", "slow")},
+					},
+				}),
+			})
+
+			risks := AnalyzeCRDs([]CRD{from}, []CRD{to})
+			Expect(risks).To(HaveLen(1))
+			Expect(risks[0].Risk).To(Equal(RiskHigh))
+			Expect(risks[0].Changes).To(Equal([]CRDChange{
+				{Version: "v1", Change: Changed, Detail: `field .spec.color no longer accepts "red"`, Risk: RiskMedium},
+				{Version: "v1", Change: Removed, Detail: "field .spec.labels removed", Risk: RiskMedium},
+				{Version: "v1", Change: Changed, Detail: `field .spec.mode is now restricted to "fast", "slow"`, Risk: RiskMedium},
+				{Version: "v1", Change: Changed, Detail: "field .spec.replicas type changed from integer to string", Risk: RiskHigh},
+				{Version: "v1", Change: Changed, Detail: "field .spec.size is now required", Risk: RiskHigh},
+			}))
+		})
+	})
+
+	When("the validation of a version is tightened", func() {
+		It("should find stricter bounds and patterns", func() {
+			ten, twenty, zero, one := 10.0, 20.0, int64(0), int64(1)
+			from := crd("v1", []string{"v1"}, map[string]*apiextensionsv1.JSONSchemaProps{
+				"v1": specSchema(apiextensionsv1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]apiextensionsv1.JSONSchemaProps{
+						"size":  {Type: "number", Minimum: &ten, Maximum: &twenty},
+						"name":  {Type: "string", Pattern: "^[a-z]+$"},
+						"tag":   {Type: "string"},
+						"items": {Type: "array", Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"}}},
+						"loose": {Type: "number", Maximum: &ten},
+					},
+				}),
+			})
+			to := crd("v1", []string{"v1"}, map[string]*apiextensionsv1.JSONSchemaProps{
+				"v1": specSchema(apiextensionsv1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]apiextensionsv1.JSONSchemaProps{
+						"size":  {Type: "number", Minimum: &ten, ExclusiveMinimum: true, Maximum: &ten},
+						"name":  {Type: "string", Pattern: "^[a-z]{1,8}$", MinLength: &zero},
+						"tag":   {Type: "string", Pattern: "^v", MinLength: &one},
+						"items": {Type: "array", MaxItems: &one, Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"}}},
+						"loose": {Type: "number", Maximum: &twenty},
+					},
+				}),
+			})
+
+			risks := AnalyzeCRDs([]CRD{from}, []CRD{to})
+			Expect(risks).To(HaveLen(1))
+			Expect(risks[0].Risk).To(Equal(RiskMedium))
+			Expect(risks[0].Changes).To(Equal([]CRDChange{
+				{Version: "v1", Change: Changed, Detail: "field .spec.items now has a maxItems of 1", Risk: RiskMedium},
+				{Version: "v1", Change: Changed, Detail: "field .spec.name pattern changed from ^[a-z]+$ to ^[a-z]{1,8}$", Risk: RiskMedium},
+				{Version: "v1", Change: Changed, Detail: "field .spec.size maximum changed from 20 to 10", Risk: RiskMedium},
+				{Version: "v1", Change: Changed, Detail: "field .spec.size minimum is now exclusive", Risk: RiskMedium},
+				{Version: "v1", Change: Changed, Detail: "field .spec.tag minLength changed from 0 to 1", Risk: RiskMedium},
+				{Version: "v1", Change: Changed, Detail: "field .spec.tag now has to match ^v", Risk: RiskMedium},
+			}))
+		})
+	})
+
+	When("versions change", func() {
+		It("should find removed versions and versions no longer served", func() {
+			schema := specSchema(apiextensionsv1.JSONSchemaProps{Type: "object"})
+			from := crd("v1beta1", []string{"v1alpha1", "v1beta1", "v1"}, map[string]*apiextensionsv1.JSONSchemaProps{
+				"v1alpha1": schema, "v1beta1": schema, "v1": schema, "v0": schema,
+			})
+			to := crd("v1", []string{"v1"}, map[string]*apiextensionsv1.JSONSchemaProps{
+				"v1alpha1": schema, "v1": schema,
+			})
+
+			risks := AnalyzeCRDs([]CRD{from}, []CRD{to})
+			Expect(risks[0].Risk).To(Equal(RiskHigh))
+			Expect(risks[0].Changes).To(ConsistOf(
+				CRDChange{Version: "v1alpha1", Change: Changed, Detail: "version no longer served", Risk: RiskMedium},
+				CRDChange{Version: "v1beta1", Change: Removed, Detail: "storage version removed", Risk: RiskHigh},
+				CRDChange{Version: "v0", Change: Removed, Detail: "version removed", Risk: RiskLow},
+				CRDChange{Version: "v1", Change: Changed, Detail: "storage version changed from v1beta1, stored objects need to be migrated", Risk: RiskLow},
+			))
+		})
+
+		It("should report removed CRDs", func() {
+			risks := AnalyzeCRDs([]CRD{{Name: "widgets.example.com"}}, nil)
+			Expect(risks).To(Equal([]CRDRisk{{
+				Name:    "widgets.example.com",
+				Risk:    RiskHigh,
+				Changes: []CRDChange{{Change: Removed, Detail: "CRD removed", Risk: RiskHigh}},
+			}}))
+		})
+	})
+
+	When("nothing changes", func() {
+		It("should report no risk", func() {
+			bundles, err := ReadBundlesFromDir("testdata/diff")
+			Expect(err).ToNot(HaveOccurred())
+			b, _ := FindBundle(bundles, "widget-operator", "1.0.0")
+			risks := AnalyzeCRDs(b.CRDs, b.CRDs)
+			Expect(risks).To(HaveLen(1))
+			Expect(risks[0].Risk).To(Equal(RiskNone))
+			Expect(risks[0].Changes).To(BeEmpty())
+		})
+	})
+
+	When("reading CRD manifests", func() {
+		It("should read the served and storage versions", func() {
+			b, err := ReadBundle("testdata/diff/operators/widget-operator/1.0.0")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(b.CRDs[0].Served).To(Equal([]string{"v1alpha1", "v1"}))
+			Expect(b.CRDs[0].Storage).To(Equal("v1"))
+			Expect(b.CRDs[0].Schemas).To(HaveKey("v1alpha1"))
+		})
+	})
+})
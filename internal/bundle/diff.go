@@ -0,0 +1,282 @@
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	operatorv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Categories of the changes between two bundles
+const (
+	DiffPermission   = "permission"
+	DiffCRD          = "crd"
+	DiffInstallMode  = "install mode"
+	DiffRelatedImage = "related image"
+	DiffAlmExample   = "alm-example"
+)
+
+// Kinds of changes between two bundles
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// BundleDiff lists the changes from a bundle to another of the same package
+type BundleDiff struct {
+	Package string
+	From    string
+	To      string
+	Changes []DiffChange
+}
+
+// DiffChange is a change from a bundle to another
+type DiffChange struct {
+	Category string
+	Change   string
+	// Subject is what changed, a permission rule, a CRD field or an image for instance
+	Subject string
+	Detail  string
+	// Breaking is set on changes that can break existing users of the operator
+	Breaking bool
+}
+
+// FindBundle returns the bundle of a package with a version, matched against the version and the CSV name
+func FindBundle(bundles []Bundle, packageName, version string) (Bundle, bool) {
+	for _, b := range bundles {
+		if b.PackageName != packageName {
+			continue
+		}
+		if b.Version == version || b.StartingCSV == version || bundleVersion(b) == strings.TrimPrefix(version, "v") {
+			return b, true
+		}
+	}
+	return Bundle{}, false
+}
+
+// DiffBundles compares the permissions, CRD schemas, install modes, related images and alm-examples of two bundles
+func DiffBundles(from, to Bundle) BundleDiff {
+	diff := BundleDiff{Package: to.PackageName, From: from.StartingCSV, To: to.StartingCSV}
+	diff.Changes = append(diff.Changes, diffPermissions(from.CSV, to.CSV)...)
+	diff.Changes = append(diff.Changes, diffCRDs(from.CRDs, to.CRDs)...)
+	diff.Changes = append(diff.Changes, diffInstallModes(from.InstallModes, to.InstallModes)...)
+	diff.Changes = append(diff.Changes, diffRelatedImages(from.RelatedImages, to.RelatedImages)...)
+	diff.Changes = append(diff.Changes, diffAlmExamples(from.CSV, to.CSV)...)
+	return diff
+}
+
+// permissionRules flattens the permissions of a CSV in one entry per scope, service account, API group, resource and
+// verb so that rules can be compared whatever the way they are grouped
+func permissionRules(csv *operatorv1alpha1.ClusterServiceVersion) map[string]bool {
+	rules := map[string]bool{}
+	if csv == nil {
+		return rules
+	}
+	strategy := csv.Spec.InstallStrategy.StrategySpec
+	scopes := map[string][]operatorv1alpha1.StrategyDeploymentPermissions{
+		"namespace": strategy.Permissions,
+		"cluster":   strategy.ClusterPermissions,
+	}
+	for scope, permissions := range scopes {
+		for _, permission := range permissions {
+			for _, rule := range permission.Rules {
+				resources := append(append([]string{}, rule.Resources...), rule.NonResourceURLs...)
+				groups := rule.APIGroups
+				if len(groups) == 0 {
+					groups = []string{""}
+				}
+				for _, group := range groups {
+					for _, resource := range resources {
+						if group != "" {
+							resource = group + "/" + resource
+						}
+						for _, verb := range rule.Verbs {
+							rules[fmt.Sprintf("%s %s: %s %s", scope, permission.ServiceAccountName, verb, resource)] = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return rules
+}
+
+func diffPermissions(from, to *operatorv1alpha1.ClusterServiceVersion) []DiffChange {
+	changes := []DiffChange{}
+	fromRules, toRules := permissionRules(from), permissionRules(to)
+	for _, rule := range sortedKeys(toRules) {
+		if !fromRules[rule] {
+			changes = append(changes, DiffChange{Category: DiffPermission, Change: Added, Subject: rule})
+		}
+	}
+	for _, rule := range sortedKeys(fromRules) {
+		if !toRules[rule] {
+			changes = append(changes, DiffChange{Category: DiffPermission, Change: Removed, Subject: rule})
+		}
+	}
+	return changes
+}
+
+// diffCRDs lists the added CRDs, versions and fields, the changes that can break existing objects come from
+// AnalyzeCRDs
+func diffCRDs(from, to []CRD) []DiffChange {
+	changes := []DiffChange{}
+	fromCRDs := map[string]CRD{}
+	for _, crd := range from {
+		fromCRDs[crd.Name] = crd
+	}
+	for _, crd := range to {
+		fromCRD, ok := fromCRDs[crd.Name]
+		if !ok {
+			changes = append(changes, DiffChange{Category: DiffCRD, Change: Added, Subject: crd.Name})
+			continue
+		}
+		for _, version := range crd.Versions {
+			if !contains(fromCRD.Versions, version) {
+				changes = append(changes, DiffChange{Category: DiffCRD, Change: Added, Subject: crd.Name + " " + version})
+				continue
+			}
+			fromFields, toFields := schemaFields(fromCRD.Schemas[version]), schemaFields(crd.Schemas[version])
+			for _, field := range sortedKeys(toFields) {
+				if !fromFields[field] {
+					changes = append(changes, DiffChange{Category: DiffCRD, Change: Added, Subject: crd.Name + " " + version, Detail: "field " + field})
+				}
+			}
+		}
+	}
+
+	for _, risk := range AnalyzeCRDs(from, to) {
+		for _, change := range risk.Changes {
+			subject := strings.TrimSpace(risk.Name + " " + change.Version)
+			changes = append(changes, DiffChange{Category: DiffCRD, Change: change.Change, Subject: subject, Detail: change.Detail, Breaking: change.Breaking()})
+		}
+	}
+	return changes
+}
+
+// schemaFields lists the paths of the properties of a schema, array items being suffixed with []
+func schemaFields(schema *apiextensionsv1.JSONSchemaProps) map[string]bool {
+	fields := map[string]bool{}
+	var walk func(schema *apiextensionsv1.JSONSchemaProps, path string)
+	walk = func(schema *apiextensionsv1.JSONSchemaProps, path string) {
+		if schema == nil {
+			return
+		}
+		for name, property := range schema.Properties {
+			property := property
+			fields[path+"."+name] = true
+			walk(&property, path+"."+name)
+		}
+		if schema.Items != nil {
+			walk(schema.Items.Schema, path+"[]")
+		}
+	}
+	walk(schema, "")
+	return fields
+}
+
+func diffInstallModes(from, to []operatorv1alpha1.InstallMode) []DiffChange {
+	changes := []DiffChange{}
+	supported := func(modes []operatorv1alpha1.InstallMode) map[string]bool {
+		supported := map[string]bool{}
+		for _, mode := range modes {
+			supported[string(mode.Type)] = mode.Supported
+		}
+		return supported
+	}
+	fromModes, toModes := supported(from), supported(to)
+	for _, mode := range sortedKeys(toModes) {
+		if toModes[mode] && !fromModes[mode] {
+			changes = append(changes, DiffChange{Category: DiffInstallMode, Change: Added, Subject: mode})
+		}
+	}
+	for _, mode := range sortedKeys(fromModes) {
+		if fromModes[mode] && !toModes[mode] {
+			changes = append(changes, DiffChange{Category: DiffInstallMode, Change: Removed, Subject: mode, Breaking: true})
+		}
+	}
+	return changes
+}
+
+func diffRelatedImages(from, to []operatorv1alpha1.RelatedImage) []DiffChange {
+	changes := []DiffChange{}
+	images := func(relatedImages []operatorv1alpha1.RelatedImage) map[string]string {
+		images := map[string]string{}
+		for _, image := range relatedImages {
+			name := image.Name
+			if name == "" {
+				name = image.Image
+			}
+			images[name] = image.Image
+		}
+		return images
+	}
+	fromImages, toImages := images(from), images(to)
+	for _, name := range sortedKeys(toImages) {
+		fromImage, ok := fromImages[name]
+		switch {
+		case !ok:
+			changes = append(changes, DiffChange{Category: DiffRelatedImage, Change: Added, Subject: name, Detail: toImages[name]})
+		case fromImage != toImages[name]:
+			changes = append(changes, DiffChange{Category: DiffRelatedImage, Change: Changed, Subject: name, Detail: fromImage + " to " + toImages[name]})
+		}
+	}
+	for _, name := range sortedKeys(fromImages) {
+		if _, ok := toImages[name]; !ok {
+			changes = append(changes, DiffChange{Category: DiffRelatedImage, Change: Removed, Subject: name, Detail: fromImages[name]})
+		}
+	}
+	return changes
+}
+
+// almExamples returns the alm-examples of a CSV keyed by apiVersion and kind
+func almExamples(csv *operatorv1alpha1.ClusterServiceVersion) map[string]map[string]interface{} {
+	examples := map[string]map[string]interface{}{}
+	if csv == nil {
+		return examples
+	}
+	var objs []map[string]interface{}
+	if err := json.Unmarshal([]byte(csv.Annotations["alm-examples"]), &objs); err != nil {
+		return examples
+	}
+	for _, obj := range objs {
+		example := unstructured.Unstructured{Object: obj}
+		examples[example.GetAPIVersion()+" "+example.GetKind()] = obj
+	}
+	return examples
+}
+
+func diffAlmExamples(from, to *operatorv1alpha1.ClusterServiceVersion) []DiffChange {
+	changes := []DiffChange{}
+	fromExamples, toExamples := almExamples(from), almExamples(to)
+	for _, example := range sortedKeys(toExamples) {
+		fromExample, ok := fromExamples[example]
+		switch {
+		case !ok:
+			changes = append(changes, DiffChange{Category: DiffAlmExample, Change: Added, Subject: example})
+		case !reflect.DeepEqual(fromExample, toExamples[example]):
+			changes = append(changes, DiffChange{Category: DiffAlmExample, Change: Changed, Subject: example})
+		}
+	}
+	for _, example := range sortedKeys(fromExamples) {
+		if _, ok := toExamples[example]; !ok {
+			changes = append(changes, DiffChange{Category: DiffAlmExample, Change: Removed, Subject: example})
+		}
+	}
+	return changes
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,87 @@
+package bundle
+
+import (
+	. "github.com/onsi/ginkgo/v2/dsl/core"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bundle diff", func() {
+	var bundles []Bundle
+
+	BeforeEach(func() {
+		var err error
+		bundles, err = ReadBundlesFromDir("testdata/diff")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	When("finding bundles", func() {
+		It("should match the version directory, the CSV name and the CSV version", func() {
+			for _, version := range []string{"2.0.0", "v2.0.0", "widget-operator.v2.0.0"} {
+				b, ok := FindBundle(bundles, "widget-operator", version)
+				Expect(ok).To(BeTrue(), version)
+				Expect(b.StartingCSV).To(Equal("widget-operator.v2.0.0"))
+			}
+			_, ok := FindBundle(bundles, "widget-operator", "3.0.0")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	When("comparing two versions of a package", func() {
+		var changes []DiffChange
+
+		BeforeEach(func() {
+			from, _ := FindBundle(bundles, "widget-operator", "1.0.0")
+			to, _ := FindBundle(bundles, "widget-operator", "2.0.0")
+			diff := DiffBundles(from, to)
+			Expect(diff.Package).To(Equal("widget-operator"))
+			Expect(diff.From).To(Equal("widget-operator.v1.0.0"))
+			Expect(diff.To).To(Equal("widget-operator.v2.0.0"))
+			changes = diff.Changes
+		})
+
+		It("should report permission changes per rule", func() {
+			Expect(changes).To(ContainElements(
+				DiffChange{Category: DiffPermission, Change: Added, Subject: "cluster widget-operator: watch example.com/gadgets"},
+				DiffChange{Category: DiffPermission, Change: Added, Subject: "cluster widget-operator: get secrets"},
+				DiffChange{Category: DiffPermission, Change: Removed, Subject: "namespace widget-operator: list configmaps"},
+			))
+			Expect(changes).ToNot(ContainElement(HaveField("Subject", "cluster widget-operator: get example.com/widgets")))
+		})
+
+		It("should report CRD changes, removed versions and fields being breaking", func() {
+			Expect(changes).To(ContainElements(
+				DiffChange{Category: DiffCRD, Change: Added, Subject: "gadgets.example.com"},
+				DiffChange{Category: DiffCRD, Change: Removed, Subject: "widgets.example.com v1alpha1", Detail: "served version removed", Breaking: true},
+				DiffChange{Category: DiffCRD, Change: Added, Subject: "widgets.example.com v1", Detail: "field .spec.mode"},
+				DiffChange{Category: DiffCRD, Change: Changed, Subject: "widgets.example.com v1", Detail: "field .spec.mode is now required", Breaking: true},
+				DiffChange{Category: DiffCRD, Change: Removed, Subject: "widgets.example.com v1", Detail: "field .spec.color removed", Breaking: true},
+				DiffChange{Category: DiffCRD, Change: Removed, Subject: "widgets.example.com v1", Detail: "field .spec.tags[].value removed", Breaking: true},
+			))
+		})
+
+		It("should report install modes no longer supported as breaking", func() {
+			Expect(changes).To(ContainElement(DiffChange{Category: DiffInstallMode, Change: Removed, Subject: "OwnNamespace", Breaking: true}))
+		})
+
+		It("should report related image changes", func() {
+			Expect(changes).To(ContainElements(
+				DiffChange{Category: DiffRelatedImage, Change: Changed, Subject: "operator", Detail: "quay.io/example/widget-operator:v1.0.0 to quay.io/example/widget-operator:v2.0.0"},
+				DiffChange{Category: DiffRelatedImage, Change: Removed, Subject: "metrics", Detail: "quay.io/example/widget-metrics:v1.0.0"},
+			))
+		})
+
+		It("should report alm-examples changes", func() {
+			Expect(changes).To(ContainElements(
+				DiffChange{Category: DiffAlmExample, Change: Added, Subject: "example.com/v1 Gadget"},
+				DiffChange{Category: DiffAlmExample, Change: Changed, Subject: "example.com/v1 Widget"},
+			))
+		})
+	})
+
+	When("comparing a bundle with itself", func() {
+		It("should report no change", func() {
+			b, _ := FindBundle(bundles, "widget-operator", "2.0.0")
+			Expect(DiffBundles(b, b).Changes).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,240 @@
+package bundle
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+
+	operatorv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Schemas of the file-based catalog blobs
+const (
+	fbcPackageSchema = "olm.package"
+	fbcChannelSchema = "olm.channel"
+	fbcBundleSchema  = "olm.bundle"
+)
+
+// Property types of the olm.bundle blobs
+const (
+	fbcPackageProperty             = "olm.package"
+	fbcBundleObjectProperty        = "olm.bundle.object"
+	fbcMaxOpenShiftVersionProperty = "olm.maxOpenShiftVersion"
+)
+
+type fbcPackage struct {
+	Name           string `json:"name"`
+	DefaultChannel string `json:"defaultChannel"`
+}
+
+type fbcChannel struct {
+	Package string            `json:"package"`
+	Name    string            `json:"name"`
+	Entries []fbcChannelEntry `json:"entries"`
+}
+
+type fbcChannelEntry struct {
+	Name      string   `json:"name"`
+	Replaces  string   `json:"replaces"`
+	Skips     []string `json:"skips"`
+	SkipRange string   `json:"skipRange"`
+}
+
+type fbcBundle struct {
+	Package       string                          `json:"package"`
+	Name          string                          `json:"name"`
+	Image         string                          `json:"image"`
+	Properties    []fbcProperty                   `json:"properties"`
+	RelatedImages []operatorv1alpha1.RelatedImage `json:"relatedImages"`
+}
+
+type fbcProperty struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// ReadBundlesFromFBC reads the bundles of a file-based catalog directory. Every JSON and YAML file of the directory
+// and its subdirectories is read for olm.package, olm.channel and olm.bundle blobs. The channels, replaces, skips and
+// skipRange of a bundle come from the channel entries, its manifests from the olm.bundle.object properties. The
+// OpenShift versions come from the com.redhat.openshift.versions annotation of the CSV, or else from the
+// olm.maxOpenShiftVersion property as every version of the same major up to that one.
+// The Path of the bundles is the catalog file they are declared in. A directory without any olm.package or
+// olm.bundle blob is not a catalog and fails.
+func ReadBundlesFromFBC(catalogDir string) ([]Bundle, error) {
+	packages := map[string]fbcPackage{}
+	channels := []fbcChannel{}
+	blobs := []fbcBundle{}
+	files := map[string]string{}
+
+	err := filepath.WalkDir(catalogDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		switch filepath.Ext(path) {
+		case ".json", ".yaml", ".yml":
+		default:
+			return nil
+		}
+
+		objs, err := decodeManifests(path)
+		if err != nil {
+			return fmt.Errorf("failed to decode catalog file %s: %s", path, err)
+		}
+		for _, obj := range objs {
+			schema, _, _ := unstructured.NestedString(obj.Object, "schema")
+			switch schema {
+			case fbcPackageSchema:
+				pkg := fbcPackage{}
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &pkg); err != nil {
+					return fmt.Errorf("invalid %s in %s: %s", schema, path, err)
+				}
+				packages[pkg.Name] = pkg
+			case fbcChannelSchema:
+				channel := fbcChannel{}
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &channel); err != nil {
+					return fmt.Errorf("invalid %s in %s: %s", schema, path, err)
+				}
+				channels = append(channels, channel)
+			case fbcBundleSchema:
+				// properties values are free-form, they are decoded as raw JSON
+				data, err := json.Marshal(obj.Object)
+				if err != nil {
+					return err
+				}
+				blob := fbcBundle{}
+				if err := json.Unmarshal(data, &blob); err != nil {
+					return fmt.Errorf("invalid %s in %s: %s", schema, path, err)
+				}
+				blobs = append(blobs, blob)
+				files[blob.Package+"/"+blob.Name] = path
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file-based catalog: %s", err)
+	}
+	if len(packages) == 0 && len(blobs) == 0 {
+		return nil, fmt.Errorf("no %s or %s found in %s", fbcPackageSchema, fbcBundleSchema, catalogDir)
+	}
+
+	// channel entries, keyed by package and bundle name
+	entries := map[string][]fbcChannelEntry{}
+	bundleChannels := map[string][]string{}
+	sort.SliceStable(channels, func(i, j int) bool { return channels[i].Name < channels[j].Name })
+	for _, channel := range channels {
+		for _, entry := range channel.Entries {
+			key := channel.Package + "/" + entry.Name
+			entries[key] = append(entries[key], entry)
+			bundleChannels[key] = append(bundleChannels[key], channel.Name)
+		}
+	}
+
+	bundles := []Bundle{}
+	for _, blob := range blobs {
+		key := blob.Package + "/" + blob.Name
+		bundle := Bundle{
+			PackageName: blob.Package,
+			Channel:     packages[blob.Package].DefaultChannel,
+			Channels:    bundleChannels[key],
+			Path:        files[key],
+		}
+		bundle.readFBCProperties(blob)
+
+		// the catalog, not the CSV, is authoritative on the bundle name and its upgrade edges
+		bundle.StartingCSV = blob.Name
+		if len(blob.RelatedImages) > 0 {
+			bundle.RelatedImages = blob.RelatedImages
+		}
+		bundle.Replaces, bundle.Skips, bundle.SkipRange = "", nil, ""
+		for _, entry := range entries[key] {
+			if bundle.Replaces == "" {
+				bundle.Replaces = entry.Replaces
+			}
+			if bundle.SkipRange == "" {
+				bundle.SkipRange = entry.SkipRange
+			}
+			for _, skip := range entry.Skips {
+				if !contains(bundle.Skips, skip) {
+					bundle.Skips = append(bundle.Skips, skip)
+				}
+			}
+		}
+
+		bundles = append(bundles, bundle)
+	}
+
+	sort.SliceStable(bundles, func(i, j int) bool {
+		if bundles[i].PackageName != bundles[j].PackageName {
+			return bundles[i].PackageName < bundles[j].PackageName
+		}
+		return versionLess(bundles[i].Version, bundles[j].Version)
+	})
+	return bundles, nil
+}
+
+// readFBCProperties sets the version, manifests and OpenShift versions of a bundle from the properties of its
+// olm.bundle blob
+func (b *Bundle) readFBCProperties(blob fbcBundle) {
+	maxOpenShiftVersion := ""
+	for _, property := range blob.Properties {
+		switch property.Type {
+		case fbcPackageProperty:
+			value := struct {
+				Version string `json:"version"`
+			}{}
+			if err := json.Unmarshal(property.Value, &value); err != nil {
+				b.Errors = append(b.Errors, FileError{File: b.Path, Err: fmt.Errorf("invalid %s property of %s: %w", property.Type, blob.Name, err)})
+				continue
+			}
+			b.Version = value.Version
+		case fbcBundleObjectProperty:
+			value := struct {
+				Data string `json:"data"`
+			}{}
+			if err := json.Unmarshal(property.Value, &value); err != nil {
+				b.Errors = append(b.Errors, FileError{File: b.Path, Err: fmt.Errorf("invalid %s property of %s: %w", property.Type, blob.Name, err)})
+				continue
+			}
+			data, err := base64.StdEncoding.DecodeString(value.Data)
+			if err != nil {
+				b.Errors = append(b.Errors, FileError{File: b.Path, Err: fmt.Errorf("invalid %s property of %s: %w", property.Type, blob.Name, err)})
+				continue
+			}
+			obj := unstructured.Unstructured{}
+			if err := obj.UnmarshalJSON(data); err != nil {
+				b.Errors = append(b.Errors, FileError{File: b.Path, Err: fmt.Errorf("invalid %s property of %s: %w", property.Type, blob.Name, err)})
+				continue
+			}
+			b.addManifest(b.Path, obj)
+		case fbcMaxOpenShiftVersionProperty:
+			// the value is usually a string but may have been written as a number
+			var value interface{}
+			if err := json.Unmarshal(property.Value, &value); err != nil {
+				b.Errors = append(b.Errors, FileError{File: b.Path, Err: fmt.Errorf("invalid %s property of %s: %w", property.Type, blob.Name, err)})
+				continue
+			}
+			maxOpenShiftVersion = fmt.Sprint(value)
+		}
+	}
+
+	if b.CSV != nil {
+		b.OcpVersions = b.CSV.Annotations[ocpVersionsAnnotation]
+	}
+	if b.OcpVersions == "" && maxOpenShiftVersion != "" {
+		max, err := ParseOcpVersion(maxOpenShiftVersion)
+		if err != nil {
+			b.Errors = append(b.Errors, FileError{File: b.Path, Err: fmt.Errorf("invalid %s property of %s: %w", fbcMaxOpenShiftVersionProperty, blob.Name, err)})
+			return
+		}
+		b.OcpVersions = fmt.Sprintf("v%d.0-%s", max.Major, max)
+	}
+}
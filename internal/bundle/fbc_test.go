@@ -0,0 +1,94 @@
+package bundle
+
+import (
+	. "github.com/onsi/ginkgo/v2/dsl/core"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("File-based catalogs", func() {
+	When("reading a file-based catalog directory", func() {
+		var bundles []Bundle
+
+		BeforeEach(func() {
+			var err error
+			bundles, err = ReadBundlesFromDir("testdata/fbc")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bundles).To(HaveLen(3))
+		})
+
+		It("should read the bundles of JSON and YAML files sorted by package and version", func() {
+			Expect(bundles[0].StartingCSV).To(Equal("gadget-operator.v0.1.0"))
+			Expect(bundles[1].StartingCSV).To(Equal("gadget-operator.v0.2.0"))
+			Expect(bundles[2].StartingCSV).To(Equal("widget-operator.v1.0.0"))
+			Expect(bundles[2].PackageName).To(Equal("widget-operator"))
+			Expect(bundles[2].Channel).To(Equal("fast"))
+			Expect(bundles[2].Version).To(Equal("1.0.0"))
+			Expect(bundles[2].Path).To(Equal("testdata/fbc/widget-operator/catalog.yaml"))
+		})
+
+		It("should read the channels and upgrade edges from the channel entries", func() {
+			b := bundles[1]
+			Expect(b.Channel).To(Equal("stable"))
+			Expect(b.Channels).To(Equal([]string{"candidate", "stable"}))
+			Expect(b.Replaces).To(Equal("gadget-operator.v0.1.0"))
+			Expect(b.Skips).To(Equal([]string{"gadget-operator.v0.1.5"}))
+			Expect(b.SkipRange).To(Equal(">=0.0.1 <0.2.0"))
+		})
+
+		It("should read the manifests of the olm.bundle.object properties", func() {
+			b := bundles[1]
+			Expect(b.Errors).To(BeEmpty())
+			Expect(b.CSV).ToNot(BeNil())
+			Expect(b.CSV.Spec.Version.String()).To(Equal("0.2.0"))
+			Expect(b.InstallModes).To(HaveLen(2))
+			Expect(b.OwnedCRDs).To(HaveLen(1))
+			Expect(b.CRDs).To(HaveLen(1))
+			Expect(b.CRDs[0].File).To(Equal("testdata/fbc/gadget-operator/catalog.json"))
+			Expect(b.CRDs[0].Name).To(Equal("gadgets.example.com"))
+			Expect(b.CRDs[0].Group).To(Equal("example.com"))
+			Expect(b.CRDs[0].Kind).To(Equal("Gadget"))
+			Expect(b.CRDs[0].Versions).To(Equal([]string{"v1"}))
+			Expect(b.Manifests).To(HaveLen(2))
+			Expect(b.RelatedImages).To(HaveLen(1))
+			Expect(b.RelatedImages[0].Image).To(Equal("quay.io/example/gadget-operator:v0.2.0"))
+		})
+
+		It("should read the OpenShift versions from the CSV annotation or the maximum version property", func() {
+			Expect(bundles[1].OcpVersions).To(Equal("v4.10-v4.12"))
+			Expect(bundles[0].OcpVersions).To(Equal("v4.0-v4.11"))
+			Expect(bundles[2].OcpVersions).To(BeEmpty())
+		})
+
+		It("should read bundles without manifests", func() {
+			Expect(bundles[0].CSV).To(BeNil())
+			Expect(bundles[0].Version).To(Equal("0.1.0"))
+			Expect(bundles[0].Channels).To(Equal([]string{"stable"}))
+		})
+
+		It("should build the upgrade graph of the catalog", func() {
+			graphs := BuildUpgradeGraphs(bundles, "gadget-operator")
+			Expect(graphs).To(HaveLen(2))
+			Expect(graphs[1].Channel).To(Equal("stable"))
+			Expect(graphs[1].Head).To(Equal("gadget-operator.v0.2.0"))
+			Expect(graphs[1].CannotReachHead).To(BeEmpty())
+		})
+	})
+
+	When("the catalog directory does not exist", func() {
+		It("should fail", func() {
+			_, err := ReadBundlesFromFBC("testdata/missing")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("the directory holds no catalog", func() {
+		It("should fail rather than return no bundles", func() {
+			dir := GinkgoT().TempDir()
+			_, err := ReadBundlesFromDir(dir)
+			Expect(err).To(MatchError(ContainSubstring("has no operators directory and can't be read as a file-based catalog: no olm.package or olm.bundle found")))
+
+			_, err = ReadBundlesFromFBC("testdata/operators")
+			Expect(err).To(MatchError("no olm.package or olm.bundle found in testdata/operators"))
+		})
+	})
+})
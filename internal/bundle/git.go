@@ -0,0 +1,182 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+
+	"github.com/opdev/opcap/internal/logger"
+)
+
+// GitOptions select the revision of a bundle repository to check out
+type GitOptions struct {
+	// Ref is a branch, tag or commit hash, the default branch of the repository when empty
+	Ref string
+	// Depth limits the fetched history to that many commits, 0 fetches the whole history.
+	// It is ignored for commit hashes, which can only be found in the whole history.
+	Depth int
+}
+
+var commitHash = regexp.MustCompile("^[0-9a-f]{40}$")
+
+// GitCloneOrPullBundles checks out a revision of a bundle repository in outputDir. Only the selected ref is fetched.
+// When outputDir already holds a clone of the repository, as a cache does, the ref is fetched again and checked out.
+// If the repository can't be reached and the clone already holds the ref, its cached revision is checked out instead.
+// The URL can be a local path, to a bare repository for instance.
+func GitCloneOrPullBundles(URL string, outputDir string, options GitOptions) error {
+	repo, err := git.PlainOpen(outputDir)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		repo, err = git.PlainInit(outputDir, false)
+		if err == nil {
+			_, err = repo.CreateRemote(&config.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{URL}})
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed opening repository: %s", err)
+	}
+
+	remote, err := repo.Remote(git.DefaultRemoteName)
+	if err != nil {
+		return fmt.Errorf("failed opening repository remote: %s", err)
+	}
+	if urls := remote.Config().URLs; len(urls) == 0 || urls[0] != URL {
+		return fmt.Errorf("%s holds a clone of another repository than %s", outputDir, URL)
+	}
+
+	refs, err := remote.List(&git.ListOptions{})
+	if err != nil {
+		hash, ok := cachedRevision(repo, options.Ref)
+		if !ok {
+			return fmt.Errorf("failed listing references of %s: %s", URL, err)
+		}
+		logger.Warnw("failed listing references of the bundle repository, checking out the cached revision", "url", URL, "ref", options.Ref, "commit", hash.String(), "error", err)
+		return checkout(repo, hash)
+	}
+	refSpec, target, err := gitRefSpec(refs, options.Ref)
+	if err != nil {
+		return err
+	}
+
+	depth := options.Depth
+	if target == "" {
+		depth = 0
+	}
+	err = remote.Fetch(&git.FetchOptions{
+		RefSpecs: []config.RefSpec{refSpec},
+		Depth:    depth,
+		Tags:     git.NoTags,
+		Force:    true,
+	})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return fmt.Errorf("failed fetching data from repository: %s", err)
+	}
+
+	hash := plumbing.NewHash(options.Ref)
+	if target != "" {
+		ref, err := repo.Reference(target, true)
+		if err != nil {
+			return fmt.Errorf("failed resolving %s: %s", target, err)
+		}
+		hash = ref.Hash()
+	}
+	return checkout(repo, hash)
+}
+
+func checkout(repo *git.Repository, hash plumbing.Hash) error {
+	workTree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	if err = workTree.Checkout(&git.CheckoutOptions{Hash: hash, Force: true}); err != nil {
+		return fmt.Errorf("failed checking out %s: %s", hash, err)
+	}
+	return nil
+}
+
+// cachedRevision returns the commit a clone already holds for a ref: the fetched branch or tag, the commit itself
+// for commit hashes, and the checked out commit when no ref is given
+func cachedRevision(repo *git.Repository, ref string) (plumbing.Hash, bool) {
+	if ref == "" {
+		head, err := repo.Head()
+		if err != nil {
+			return plumbing.ZeroHash, false
+		}
+		return head.Hash(), true
+	}
+
+	for _, name := range []plumbing.ReferenceName{
+		plumbing.NewRemoteReferenceName(git.DefaultRemoteName, ref),
+		plumbing.NewTagReferenceName(ref),
+	} {
+		if r, err := repo.Reference(name, true); err == nil {
+			return r.Hash(), true
+		}
+	}
+
+	if commitHash.MatchString(ref) {
+		if _, err := repo.CommitObject(plumbing.NewHash(ref)); err == nil {
+			return plumbing.NewHash(ref), true
+		}
+	}
+	return plumbing.ZeroHash, false
+}
+
+// gitRefSpec returns the refspec fetching a ref of the remote and the local reference it is fetched to.
+// The local reference is empty for commit hashes, which are fetched along every branch.
+func gitRefSpec(refs []*plumbing.Reference, ref string) (config.RefSpec, plumbing.ReferenceName, error) {
+	remoteRefs := map[plumbing.ReferenceName]*plumbing.Reference{}
+	for _, r := range refs {
+		remoteRefs[r.Name()] = r
+	}
+
+	if ref == "" {
+		head, ok := remoteRefs[plumbing.HEAD]
+		if !ok || head.Type() != plumbing.SymbolicReference {
+			return "", "", fmt.Errorf("failed finding the default branch of the repository")
+		}
+		ref = head.Target().Short()
+	}
+
+	branch := plumbing.NewBranchReferenceName(ref)
+	tag := plumbing.NewTagReferenceName(ref)
+	switch {
+	case remoteRefs[branch] != nil:
+		target := plumbing.NewRemoteReferenceName(git.DefaultRemoteName, ref)
+		return config.RefSpec(fmt.Sprintf("+%s:%s", branch, target)), target, nil
+	case remoteRefs[tag] != nil:
+		return config.RefSpec(fmt.Sprintf("+%s:%s", tag, tag)), tag, nil
+	case commitHash.MatchString(ref):
+		return config.RefSpec(fmt.Sprintf("+refs/heads/*:refs/remotes/%s/*", git.DefaultRemoteName)), "", nil
+	}
+	return "", "", fmt.Errorf("no branch, tag or commit %s in the repository", ref)
+}
+
+// BundlesCacheDir returns the directory caching the clone of a bundle repository under cacheDir,
+// opcap/bundles in the user cache directory when cacheDir is empty
+func BundlesCacheDir(cacheDir string, URL string) (string, error) {
+	if cacheDir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return "", err
+		}
+		cacheDir = filepath.Join(userCacheDir, "opcap", "bundles")
+	}
+	return filepath.Join(cacheDir, cacheDirName(URL)), nil
+}
+
+var unsafePathChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// cacheDirName turns a repository URL or path into a directory name
+func cacheDirName(URL string) string {
+	if i := strings.Index(URL, "://"); i >= 0 {
+		URL = URL[i+3:]
+	}
+	return strings.Trim(unsafePathChars.ReplaceAllString(strings.TrimSuffix(URL, ".git"), "_"), "_.")
+}
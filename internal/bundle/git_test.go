@@ -0,0 +1,141 @@
+package bundle
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	. "github.com/onsi/ginkgo/v2/dsl/core"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bundle repositories", func() {
+	var bareRepo string
+	var commits []plumbing.Hash
+
+	// commit writes the version file of the source repository and commits it
+	commit := func(repo *git.Repository, dir, version string) plumbing.Hash {
+		Expect(os.WriteFile(filepath.Join(dir, "version"), []byte(version), 0o644)).To(Succeed())
+		workTree, err := repo.Worktree()
+		Expect(err).ToNot(HaveOccurred())
+		_, err = workTree.Add("version")
+		Expect(err).ToNot(HaveOccurred())
+		hash, err := workTree.Commit(version, &git.CommitOptions{
+			Author: &object.Signature{Name: "opcap", Email: "opcap@example.com", When: time.Now()},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		return hash
+	}
+
+	readVersion := func(dir string) string {
+		version, err := os.ReadFile(filepath.Join(dir, "version"))
+		Expect(err).ToNot(HaveOccurred())
+		return string(version)
+	}
+
+	BeforeEach(func() {
+		sourceDir := GinkgoT().TempDir()
+		source, err := git.PlainInit(sourceDir, false)
+		Expect(err).ToNot(HaveOccurred())
+
+		commits = []plumbing.Hash{commit(source, sourceDir, "v1")}
+		_, err = source.CreateTag("v1", commits[0], nil)
+		Expect(err).ToNot(HaveOccurred())
+		commits = append(commits, commit(source, sourceDir, "v2"))
+
+		workTree, err := source.Worktree()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(workTree.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature"), Create: true})).To(Succeed())
+		commits = append(commits, commit(source, sourceDir, "feature"))
+		Expect(workTree.Checkout(&git.CheckoutOptions{Branch: plumbing.Master})).To(Succeed())
+
+		bareRepo = filepath.Join(GinkgoT().TempDir(), "bundles.git")
+		_, err = git.PlainClone(bareRepo, true, &git.CloneOptions{URL: sourceDir})
+		Expect(err).ToNot(HaveOccurred())
+		bare, err := git.PlainOpen(bareRepo)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bare.Fetch(&git.FetchOptions{RefSpecs: []config.RefSpec{"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*"}})).To(Or(Succeed(), MatchError(git.NoErrAlreadyUpToDate)))
+	})
+
+	When("cloning a local bare repository", func() {
+		It("should check out the default branch with a shallow clone", func() {
+			dir := GinkgoT().TempDir()
+			Expect(GitCloneOrPullBundles(bareRepo, dir, GitOptions{Depth: 1})).To(Succeed())
+			Expect(readVersion(dir)).To(Equal("v2"))
+
+			repo, err := git.PlainOpen(dir)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = repo.CommitObject(commits[0])
+			Expect(err).To(HaveOccurred(), "the history before the checked out commit should not be fetched")
+		})
+
+		It("should check out a branch", func() {
+			dir := GinkgoT().TempDir()
+			Expect(GitCloneOrPullBundles(bareRepo, dir, GitOptions{Ref: "feature", Depth: 1})).To(Succeed())
+			Expect(readVersion(dir)).To(Equal("feature"))
+		})
+
+		It("should check out a tag", func() {
+			dir := GinkgoT().TempDir()
+			Expect(GitCloneOrPullBundles(bareRepo, dir, GitOptions{Ref: "v1", Depth: 1})).To(Succeed())
+			Expect(readVersion(dir)).To(Equal("v1"))
+		})
+
+		It("should check out a commit", func() {
+			dir := GinkgoT().TempDir()
+			Expect(GitCloneOrPullBundles(bareRepo, dir, GitOptions{Ref: commits[0].String(), Depth: 1})).To(Succeed())
+			Expect(readVersion(dir)).To(Equal("v1"))
+		})
+
+		It("should fail on an unknown ref", func() {
+			err := GitCloneOrPullBundles(bareRepo, GinkgoT().TempDir(), GitOptions{Ref: "unknown"})
+			Expect(err).To(MatchError("no branch, tag or commit unknown in the repository"))
+		})
+	})
+
+	When("the output directory already holds a clone", func() {
+		It("should check out another ref", func() {
+			dir := GinkgoT().TempDir()
+			Expect(GitCloneOrPullBundles(bareRepo, dir, GitOptions{Ref: "v1", Depth: 1})).To(Succeed())
+			Expect(GitCloneOrPullBundles(bareRepo, dir, GitOptions{Depth: 1})).To(Succeed())
+			Expect(readVersion(dir)).To(Equal("v2"))
+		})
+
+		It("should check out the cached ref when the repository can't be reached", func() {
+			dir := GinkgoT().TempDir()
+			Expect(GitCloneOrPullBundles(bareRepo, dir, GitOptions{Ref: "v1", Depth: 1})).To(Succeed())
+			Expect(GitCloneOrPullBundles(bareRepo, dir, GitOptions{Ref: "feature", Depth: 1})).To(Succeed())
+			Expect(os.RemoveAll(bareRepo)).To(Succeed())
+
+			Expect(GitCloneOrPullBundles(bareRepo, dir, GitOptions{Ref: "v1"})).To(Succeed())
+			Expect(readVersion(dir)).To(Equal("v1"))
+			Expect(GitCloneOrPullBundles(bareRepo, dir, GitOptions{})).To(Succeed())
+			Expect(readVersion(dir)).To(Equal("v1"))
+			Expect(GitCloneOrPullBundles(bareRepo, dir, GitOptions{Ref: commits[2].String()})).To(Succeed())
+			Expect(readVersion(dir)).To(Equal("feature"))
+			Expect(GitCloneOrPullBundles(bareRepo, dir, GitOptions{Ref: "master"})).To(MatchError(HavePrefix("failed listing references of")))
+		})
+
+		It("should fail when it is a clone of another repository", func() {
+			dir := GinkgoT().TempDir()
+			Expect(GitCloneOrPullBundles(bareRepo, dir, GitOptions{Depth: 1})).To(Succeed())
+			Expect(GitCloneOrPullBundles(bareRepo+"-mirror", dir, GitOptions{Depth: 1})).ToNot(Succeed())
+		})
+	})
+
+	When("caching repositories", func() {
+		It("should store each repository in its own directory", func() {
+			dir, err := BundlesCacheDir("/cache", "https://github.com/redhat-openshift-ecosystem/certified-operators.git")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dir).To(Equal("/cache/github.com_redhat-openshift-ecosystem_certified-operators"))
+
+			dir, err = BundlesCacheDir("/cache", "/srv/mirrors/bundles.git")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dir).To(Equal("/cache/srv_mirrors_bundles"))
+		})
+	})
+})
@@ -0,0 +1,223 @@
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Severities of the problems found by LintBundle
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+)
+
+// Annotations of the bundle metadata
+const (
+	mediaTypeAnnotation      = "operators.operatorframework.io.bundle.mediatype.v1"
+	packageAnnotation        = "operators.operatorframework.io.bundle.package.v1"
+	channelsAnnotation       = "operators.operatorframework.io.bundle.channels.v1"
+	defaultChannelAnnotation = "operators.operatorframework.io.bundle.channel.default.v1"
+	ocpVersionsAnnotation    = "com.redhat.openshift.versions"
+)
+
+// supportedKinds are the kinds OLM accepts in the manifests directory of a registry+v1 bundle
+var supportedKinds = map[string]bool{
+	"ClusterServiceVersion":    true,
+	"CustomResourceDefinition": true,
+	"ConfigMap":                true,
+	"Secret":                   true,
+	"Service":                  true,
+	"ServiceAccount":           true,
+	"ClusterRole":              true,
+	"ClusterRoleBinding":       true,
+	"Role":                     true,
+	"RoleBinding":              true,
+	"PrometheusRule":           true,
+	"ServiceMonitor":           true,
+	"PodDisruptionBudget":      true,
+	"PriorityClass":            true,
+	"VerticalPodAutoscaler":    true,
+	"ConsoleYAMLSample":        true,
+	"ConsoleQuickStart":        true,
+	"ConsoleCLIDownload":       true,
+	"ConsoleLink":              true,
+}
+
+// LintProblem is a structural problem found in a bundle
+type LintProblem struct {
+	Severity string
+	// File is relative to the bundle directory
+	File    string
+	Message string
+}
+
+type bundleLinter struct {
+	bundle Bundle
+	// packageDir and versionDir are set when the bundle is stored under operators/<package>/<version>
+	packageDir string
+	versionDir string
+	problems   []LintProblem
+}
+
+func (l *bundleLinter) report(severity, file, format string, args ...interface{}) {
+	l.problems = append(l.problems, LintProblem{Severity: severity, File: file, Message: fmt.Sprintf(format, args...)})
+}
+
+// LintBundle checks a bundle directory, holding the manifests and metadata directories, without a cluster.
+// It reports invalid annotations, CSV and CRD manifests that don't match, invalid alm-examples and manifests
+// OLM can't install. An error is only returned when the bundle directory can't be read.
+func LintBundle(bundleDir string) ([]LintProblem, error) {
+	bundle, err := ReadBundle(bundleDir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read bundle directory: %v", err)
+	}
+
+	l := &bundleLinter{bundle: bundle}
+	if absDir, err := filepath.Abs(bundleDir); err == nil && filepath.Base(filepath.Dir(filepath.Dir(absDir))) == "operators" {
+		l.packageDir = filepath.Base(filepath.Dir(absDir))
+		l.versionDir = filepath.Base(absDir)
+	}
+
+	for _, fileErr := range bundle.Errors {
+		l.report(LintError, fileErr.File, "%v", fileErr.Err)
+	}
+	if bundle.Annotations != nil {
+		l.lintAnnotations()
+	}
+
+	csvFiles := []string{}
+	for _, manifest := range bundle.Manifests {
+		switch {
+		case manifest.APIVersion == "" || manifest.Kind == "":
+			l.report(LintError, manifest.File, "manifest without apiVersion or kind")
+		case manifest.Kind == "ClusterServiceVersion":
+			csvFiles = append(csvFiles, manifest.File)
+		case !supportedKinds[manifest.Kind]:
+			l.report(LintWarning, manifest.File, "kind %s %s is not supported in bundles by OLM", manifest.Kind, manifest.Name)
+		}
+	}
+
+	switch {
+	case len(csvFiles) == 0:
+		l.report(LintError, "manifests", "no ClusterServiceVersion found")
+	case len(csvFiles) > 1:
+		l.report(LintError, "manifests", "%d ClusterServiceVersions found, a bundle holds a single one", len(csvFiles))
+	case bundle.CSV != nil:
+		l.lintCSV(csvFiles[0])
+	}
+
+	return l.problems, nil
+}
+
+func (l *bundleLinter) lintAnnotations() {
+	annotations := l.bundle.Annotations
+	for _, key := range []string{mediaTypeAnnotation, packageAnnotation, channelsAnnotation} {
+		if annotations[key] == "" {
+			l.report(LintError, annotationsPath, "missing annotation %s", key)
+		}
+	}
+	if mediaType := annotations[mediaTypeAnnotation]; mediaType != "" && mediaType != "registry+v1" {
+		l.report(LintWarning, annotationsPath, "unexpected media type %s, expected registry+v1", mediaType)
+	}
+
+	switch {
+	case l.bundle.Channel == "":
+		l.report(LintWarning, annotationsPath, "missing annotation %s", defaultChannelAnnotation)
+	case len(l.bundle.Channels) > 0 && !contains(l.bundle.Channels, l.bundle.Channel):
+		l.report(LintError, annotationsPath, "default channel %s is not one of the bundle channels %s", l.bundle.Channel, annotations[channelsAnnotation])
+	}
+
+	if l.bundle.OcpVersions != "" {
+		if _, err := ParseOcpVersionRange(l.bundle.OcpVersions); err != nil {
+			l.report(LintError, annotationsPath, "invalid %s annotation: %v", ocpVersionsAnnotation, err)
+		}
+	}
+
+	if packageName := l.bundle.PackageName; l.packageDir != "" && packageName != "" && packageName != l.packageDir {
+		l.report(LintError, annotationsPath, "package %s does not match the package directory %s", packageName, l.packageDir)
+	}
+}
+
+func (l *bundleLinter) lintCSV(file string) {
+	csv := l.bundle.CSV
+	if csv.Name == "" {
+		l.report(LintError, file, "ClusterServiceVersion has no name")
+	}
+	if csv.Spec.Version.String() == "0.0.0" {
+		l.report(LintError, file, "ClusterServiceVersion has no spec.version")
+	} else if l.versionDir != "" && strings.TrimPrefix(l.versionDir, "v") != csv.Spec.Version.String() {
+		l.report(LintWarning, file, "spec.version %s does not match the bundle directory %s", csv.Spec.Version.String(), l.versionDir)
+	}
+
+	if len(l.bundle.InstallModes) == 0 {
+		l.report(LintError, file, "no install modes declared")
+	} else {
+		supported := false
+		for _, mode := range l.bundle.InstallModes {
+			supported = supported || mode.Supported
+		}
+		if !supported {
+			l.report(LintError, file, "none of the install modes is supported")
+		}
+	}
+
+	if len(csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs) == 0 {
+		l.report(LintError, file, "no deployments in the install strategy")
+	}
+
+	crds := map[string]CRD{}
+	for _, crd := range l.bundle.CRDs {
+		crds[crd.Name] = crd
+	}
+
+	ownedKinds := []string{}
+	for _, owned := range l.bundle.OwnedCRDs {
+		ownedKinds = append(ownedKinds, owned.Kind)
+		crd, ok := crds[owned.Name]
+		switch {
+		case !ok:
+			l.report(LintError, file, "owned CRD %s is missing from the manifests directory", owned.Name)
+		case crd.Kind != owned.Kind:
+			l.report(LintError, file, "owned CRD %s has kind %s in %s, not %s", owned.Name, crd.Kind, crd.File, owned.Kind)
+		case !contains(crd.Versions, owned.Version):
+			l.report(LintError, file, "owned CRD %s has no version %s in %s", owned.Name, owned.Version, crd.File)
+		}
+	}
+
+	for _, crd := range l.bundle.CRDs {
+		owned := false
+		for _, ownedCRD := range l.bundle.OwnedCRDs {
+			owned = owned || ownedCRD.Name == crd.Name
+		}
+		if !owned {
+			l.report(LintWarning, crd.File, "CRD %s is not owned by the ClusterServiceVersion", crd.Name)
+		}
+	}
+
+	if packageName := l.bundle.PackageName; packageName != "" && !strings.HasPrefix(csv.Name, packageName) {
+		l.report(LintWarning, file, "ClusterServiceVersion %s is not named after the package %s", csv.Name, packageName)
+	}
+
+	almExamples, ok := csv.Annotations["alm-examples"]
+	if !ok {
+		if len(ownedKinds) > 0 {
+			l.report(LintWarning, file, "no alm-examples annotation")
+		}
+		return
+	}
+	var examples []map[string]interface{}
+	if err := json.Unmarshal([]byte(almExamples), &examples); err != nil {
+		l.report(LintError, file, "invalid alm-examples JSON: %v", err)
+		return
+	}
+	for _, example := range examples {
+		kind := (&unstructured.Unstructured{Object: example}).GetKind()
+		if !contains(ownedKinds, kind) {
+			l.report(LintWarning, file, "alm-examples kind %s is not an owned CRD", kind)
+		}
+	}
+}
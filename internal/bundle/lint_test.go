@@ -0,0 +1,57 @@
+package bundle
+
+import (
+	. "github.com/onsi/ginkgo/v2/dsl/core"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bundle linting", func() {
+	When("linting a valid bundle", func() {
+		It("should find no problems", func() {
+			problems, err := LintBundle("testdata/operators/acc-operator/21.10.19")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(problems).To(BeEmpty())
+		})
+	})
+
+	When("linting a broken bundle", func() {
+		It("should report every problem", func() {
+			problems, err := LintBundle("testdata/lint/broken-bundle")
+			Expect(err).ToNot(HaveOccurred())
+
+			messages := []string{}
+			for _, problem := range problems {
+				messages = append(messages, problem.Severity+" "+problem.File+": "+problem.Message)
+			}
+			Expect(messages).To(ConsistOf(
+				"error metadata/annotations.yaml: default channel stable is not one of the bundle channels alpha",
+				`error metadata/annotations.yaml: invalid com.redhat.openshift.versions annotation: invalid OpenShift version "v4.x": strconv.Atoi: parsing "x": invalid syntax`,
+				"warning manifests/extra.yaml: kind Deployment extra-deployment is not supported in bundles by OLM",
+				"error manifests/extra.yaml: manifest without apiVersion or kind",
+				"error manifests/broken-operator.clusterserviceversion.yaml: no install modes declared",
+				"error manifests/broken-operator.clusterserviceversion.yaml: owned CRD widgets.example.com has no version v1 in manifests/example.com_widgets.yaml",
+				"error manifests/broken-operator.clusterserviceversion.yaml: owned CRD gadgets.example.com is missing from the manifests directory",
+				"error manifests/broken-operator.clusterserviceversion.yaml: invalid alm-examples JSON: invalid character '}' looking for beginning of object key string",
+			))
+		})
+	})
+
+	When("linting a bundle without alm-examples", func() {
+		It("should still check the CSV is named after the package", func() {
+			problems, err := LintBundle("testdata/lint/misnamed-bundle")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(problems).To(ConsistOf(LintProblem{
+				Severity: LintWarning,
+				File:     "manifests/widget-operator.clusterserviceversion.yaml",
+				Message:  "ClusterServiceVersion widget-operator.v1.0.0 is not named after the package gizmo-operator",
+			}))
+		})
+	})
+
+	When("linting a missing directory", func() {
+		It("should fail", func() {
+			_, err := LintBundle("testdata/lint/missing-bundle")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,165 @@
+package bundle
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/blang/semver/v4"
+)
+
+// OcpVersion is an OpenShift minor version such as 4.11
+type OcpVersion struct {
+	Major int
+	Minor int
+}
+
+func (v OcpVersion) String() string {
+	return fmt.Sprintf("v%d.%d", v.Major, v.Minor)
+}
+
+func (v OcpVersion) less(other OcpVersion) bool {
+	return v.Major < other.Major || (v.Major == other.Major && v.Minor < other.Minor)
+}
+
+// ParseOcpVersion parses versions such as v4.11, 4.11 or 4.11.3. Only the major and minor parts are kept.
+func ParseOcpVersion(version string) (OcpVersion, error) {
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(version), "v"), ".")
+	if len(parts) < 2 {
+		return OcpVersion{}, fmt.Errorf("invalid OpenShift version %q", version)
+	}
+
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return OcpVersion{}, fmt.Errorf("invalid OpenShift version %q: %v", version, err)
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return OcpVersion{}, fmt.Errorf("invalid OpenShift version %q: %v", version, err)
+	}
+
+	return OcpVersion{Major: major, Minor: minor}, nil
+}
+
+// OcpVersionRange is the set of OpenShift versions a bundle declares support for in its
+// com.redhat.openshift.versions annotation
+type OcpVersionRange struct {
+	Min OcpVersion
+	// Max is the last supported version, nil when every version from Min on is supported
+	Max *OcpVersion
+}
+
+// ParseOcpVersionRange parses the com.redhat.openshift.versions annotation:
+// v4.8 means 4.8 and later, =v4.8 only 4.8, v4.8-v4.11 4.8 to 4.11 included,
+// and the legacy comma separated lists such as v4.7,v4.8 mean the lowest listed version and later.
+func ParseOcpVersionRange(versions string) (OcpVersionRange, error) {
+	versions = strings.TrimSpace(versions)
+	if versions == "" {
+		return OcpVersionRange{}, fmt.Errorf("no OpenShift versions declared")
+	}
+
+	switch {
+	case strings.HasPrefix(versions, "="):
+		version, err := ParseOcpVersion(strings.TrimPrefix(versions, "="))
+		if err != nil {
+			return OcpVersionRange{}, err
+		}
+		return OcpVersionRange{Min: version, Max: &version}, nil
+
+	case strings.Contains(versions, "-"):
+		bounds := strings.SplitN(versions, "-", 2)
+		min, err := ParseOcpVersion(bounds[0])
+		if err != nil {
+			return OcpVersionRange{}, err
+		}
+		max, err := ParseOcpVersion(bounds[1])
+		if err != nil {
+			return OcpVersionRange{}, err
+		}
+		if max.less(min) {
+			return OcpVersionRange{}, fmt.Errorf("invalid OpenShift version range %q", versions)
+		}
+		return OcpVersionRange{Min: min, Max: &max}, nil
+
+	case strings.Contains(versions, ","):
+		var min *OcpVersion
+		for _, v := range strings.Split(versions, ",") {
+			version, err := ParseOcpVersion(v)
+			if err != nil {
+				return OcpVersionRange{}, err
+			}
+			if min == nil || version.less(*min) {
+				min = &version
+			}
+		}
+		return OcpVersionRange{Min: *min}, nil
+	}
+
+	version, err := ParseOcpVersion(versions)
+	if err != nil {
+		return OcpVersionRange{}, err
+	}
+	return OcpVersionRange{Min: version}, nil
+}
+
+// Includes tells whether an OpenShift version, e.g. the version of the cluster under test, is in the range
+func (r OcpVersionRange) Includes(version string) (bool, error) {
+	v, err := ParseOcpVersion(version)
+	if err != nil {
+		return false, err
+	}
+	if v.less(r.Min) {
+		return false, nil
+	}
+	if r.Max != nil && r.Max.less(v) {
+		return false, nil
+	}
+	return true, nil
+}
+
+func (r OcpVersionRange) String() string {
+	switch {
+	case r.Max == nil:
+		return r.Min.String() + " and later"
+	case *r.Max == r.Min:
+		return r.Min.String() + " only"
+	}
+	return r.Min.String() + " to " + r.Max.String()
+}
+
+// LatestBundle returns the bundle with the highest version of a package, preferring bundles whose default channel
+// is the given channel. Versions that aren't semantic versions are compared as strings.
+func LatestBundle(bundles []Bundle, packageName, channel string) (Bundle, bool) {
+	var latest Bundle
+	found := false
+
+	for _, b := range bundles {
+		if b.PackageName != packageName {
+			continue
+		}
+		if !found {
+			latest, found = b, true
+			continue
+		}
+		if (b.Channel == channel) != (latest.Channel == channel) {
+			if b.Channel == channel {
+				latest = b
+			}
+			continue
+		}
+		if versionLess(latest.Version, b.Version) {
+			latest = b
+		}
+	}
+
+	return latest, found
+}
+
+func versionLess(a, b string) bool {
+	va, errA := semver.ParseTolerant(a)
+	vb, errB := semver.ParseTolerant(b)
+	if errA != nil || errB != nil {
+		return a < b
+	}
+	return va.LT(vb)
+}
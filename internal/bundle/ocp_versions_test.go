@@ -0,0 +1,95 @@
+package bundle
+
+import (
+	. "github.com/onsi/ginkgo/v2/dsl/core"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OpenShift versions", func() {
+	When("parsing the com.redhat.openshift.versions annotation", func() {
+		It("should read a single version as that version and later", func() {
+			versions, err := ParseOcpVersionRange("v4.8")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(versions.Min).To(Equal(OcpVersion{Major: 4, Minor: 8}))
+			Expect(versions.Max).To(BeNil())
+			Expect(versions.String()).To(Equal("v4.8 and later"))
+		})
+
+		It("should read an equal sign as that version only", func() {
+			versions, err := ParseOcpVersionRange("=v4.9")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(versions.String()).To(Equal("v4.9 only"))
+		})
+
+		It("should read a range with both bounds included", func() {
+			versions, err := ParseOcpVersionRange("v4.6-v4.8")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(versions.String()).To(Equal("v4.6 to v4.8"))
+		})
+
+		It("should read a comma separated list as the lowest version and later", func() {
+			versions, err := ParseOcpVersionRange("v4.8,v4.7")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(versions.String()).To(Equal("v4.7 and later"))
+		})
+
+		It("should fail on invalid annotations", func() {
+			for _, annotation := range []string{"", "four", "v4.10-v4.8", "v4.x"} {
+				_, err := ParseOcpVersionRange(annotation)
+				Expect(err).To(HaveOccurred(), annotation)
+			}
+		})
+	})
+
+	When("comparing the cluster version", func() {
+		It("should tell whether the range includes it", func() {
+			versions, err := ParseOcpVersionRange("v4.6-v4.10")
+			Expect(err).ToNot(HaveOccurred())
+
+			for version, included := range map[string]bool{
+				"4.5":     false,
+				"4.6":     true,
+				"v4.9":    true,
+				"4.10.12": true,
+				"4.11":    false,
+				"5.0":     false,
+			} {
+				Expect(versions.Includes(version)).To(Equal(included), version)
+			}
+		})
+
+		It("should fail on an invalid cluster version", func() {
+			versions, err := ParseOcpVersionRange("v4.6")
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = versions.Includes("")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("looking for the latest bundle of a package", func() {
+		bundles := []Bundle{
+			{PackageName: "other-operator", Version: "9.0.0", Channel: "stable"},
+			{PackageName: "test-operator", Version: "1.2.0", Channel: "stable", OcpVersions: "v4.8"},
+			{PackageName: "test-operator", Version: "1.10.0", Channel: "stable", OcpVersions: "v4.10"},
+			{PackageName: "test-operator", Version: "2.0.0", Channel: "alpha", OcpVersions: "v4.11"},
+		}
+
+		It("should prefer the highest version on the channel", func() {
+			b, ok := LatestBundle(bundles, "test-operator", "stable")
+			Expect(ok).To(BeTrue())
+			Expect(b.Version).To(Equal("1.10.0"))
+		})
+
+		It("should fall back on other channels", func() {
+			b, ok := LatestBundle(bundles, "test-operator", "beta")
+			Expect(ok).To(BeTrue())
+			Expect(b.Version).To(Equal("2.0.0"))
+		})
+
+		It("should not find unknown packages", func() {
+			_, ok := LatestBundle(bundles, "unknown-operator", "stable")
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
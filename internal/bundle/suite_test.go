@@ -5,9 +5,12 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+
+	"github.com/opdev/opcap/internal/logger"
 )
 
 func TestBundle(t *testing.T) {
 	RegisterFailHandler(Fail)
+	Expect(logger.InitLogger("error")).To(Succeed())
 	RunSpecs(t, "Bundle Suite")
 }
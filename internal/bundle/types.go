@@ -1,9 +1,78 @@
 package bundle
 
+import (
+	operatorv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
 type Bundle struct {
 	PackageName string
 	Channel     string
 	Version     string
 	StartingCSV string
 	OcpVersions string
+	// Path is the directory of the bundle version, holding its manifests and metadata, or the catalog file
+	// declaring the bundle in a file-based catalog
+	Path string
+
+	// Channels lists every channel of the bundle, Channel being the default one
+	Channels []string
+	// Annotations are the annotations of metadata/annotations.yaml
+	Annotations map[string]string
+	// CSV is the ClusterServiceVersion of the bundle, nil when it has none
+	CSV           *operatorv1alpha1.ClusterServiceVersion
+	OwnedCRDs     []operatorv1alpha1.CRDDescription
+	RequiredCRDs  []operatorv1alpha1.CRDDescription
+	InstallModes  []operatorv1alpha1.InstallMode
+	RelatedImages []operatorv1alpha1.RelatedImage
+	Replaces      string
+	Skips         []string
+	// SkipRange is the olm.skipRange annotation of the CSV, the semver range of versions it can upgrade from
+	SkipRange      string
+	MinKubeVersion string
+	// CRDs are the CustomResourceDefinitions of the manifests directory
+	CRDs []CRD
+	// Manifests lists every object of the manifests directory, the CSV and CRDs included
+	Manifests []Manifest
+	// Errors are the files of the bundle that could not be read or decoded
+	Errors []FileError
+}
+
+// CRD is a CustomResourceDefinition manifest of a bundle, either apiextensions.k8s.io/v1 or v1beta1
+type CRD struct {
+	// File is relative to the bundle directory
+	File     string
+	Name     string
+	Group    string
+	Kind     string
+	Versions []string
+	// Served are the versions served by the API server, Storage the version objects are stored in
+	Served  []string
+	Storage string
+	// Schemas are the OpenAPI v3 schemas of the versions, keyed by version name. Versions without a schema are missing.
+	Schemas map[string]*apiextensionsv1.JSONSchemaProps
+}
+
+// Manifest is an object of the manifests directory of a bundle
+type Manifest struct {
+	// File is relative to the bundle directory
+	File       string
+	APIVersion string
+	Kind       string
+	Name       string
+}
+
+// FileError is a bundle file that could not be read or decoded
+type FileError struct {
+	// File is relative to the bundle directory
+	File string
+	Err  error
+}
+
+func (e FileError) Error() string {
+	return e.File + ": " + e.Err.Error()
+}
+
+func (e FileError) Unwrap() error {
+	return e.Err
 }
@@ -0,0 +1,296 @@
+package bundle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver/v4"
+)
+
+// Kinds of upgrade edges
+const (
+	UpgradeReplaces  = "replaces"
+	UpgradeSkips     = "skips"
+	UpgradeSkipRange = "skipRange"
+)
+
+// UpgradeGraph is the upgrade graph of a package channel built from the replaces, skips and olm.skipRange of its CSVs
+type UpgradeGraph struct {
+	Package string        `json:"package"`
+	Channel string        `json:"channel"`
+	Head    string        `json:"head"`
+	Nodes   []UpgradeNode `json:"nodes"`
+	Edges   []UpgradeEdge `json:"edges"`
+	// DeadEnds are the bundles, the head apart, nothing upgrades from
+	DeadEnds []string `json:"deadEnds"`
+	// Unreachable are the bundles an installation of the oldest bundle can't upgrade to
+	Unreachable []string `json:"unreachable"`
+	// Cycles are groups of bundles upgrading to each other
+	Cycles [][]string `json:"cycles"`
+	// CannotReachHead are the bundles without an upgrade path to the head
+	CannotReachHead []string `json:"cannotReachHead"`
+	// MissingReferences are replaces and skips pointing to bundles that aren't in the package
+	MissingReferences []string `json:"missingReferences"`
+}
+
+// UpgradeNode is a bundle of the channel
+type UpgradeNode struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// UpgradeEdge is an upgrade from one bundle to another
+type UpgradeEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Type string `json:"type"`
+}
+
+// BuildUpgradeGraphs builds the upgrade graph of every channel of a package
+func BuildUpgradeGraphs(bundles []Bundle, packageName string) []UpgradeGraph {
+	channels := map[string][]Bundle{}
+	inPackage := map[string]bool{}
+	for _, b := range bundles {
+		if b.PackageName != packageName || b.StartingCSV == "" {
+			continue
+		}
+		inPackage[b.StartingCSV] = true
+		bundleChannels := b.Channels
+		if len(bundleChannels) == 0 && b.Channel != "" {
+			bundleChannels = []string{b.Channel}
+		}
+		for _, channel := range bundleChannels {
+			channels[channel] = append(channels[channel], b)
+		}
+	}
+
+	names := make([]string, 0, len(channels))
+	for channel := range channels {
+		names = append(names, channel)
+	}
+	sort.Strings(names)
+
+	graphs := []UpgradeGraph{}
+	for _, channel := range names {
+		graphs = append(graphs, buildUpgradeGraph(packageName, channel, channels[channel], inPackage))
+	}
+	return graphs
+}
+
+func buildUpgradeGraph(packageName, channel string, bundles []Bundle, inPackage map[string]bool) UpgradeGraph {
+	graph := UpgradeGraph{
+		Package:           packageName,
+		Channel:           channel,
+		Nodes:             []UpgradeNode{},
+		Edges:             []UpgradeEdge{},
+		DeadEnds:          []string{},
+		Unreachable:       []string{},
+		Cycles:            [][]string{},
+		CannotReachHead:   []string{},
+		MissingReferences: []string{},
+	}
+
+	sort.SliceStable(bundles, func(i, j int) bool { return versionLess(bundleVersion(bundles[i]), bundleVersion(bundles[j])) })
+
+	inChannel := map[string]bool{}
+	for _, b := range bundles {
+		if inChannel[b.StartingCSV] {
+			continue
+		}
+		inChannel[b.StartingCSV] = true
+		graph.Nodes = append(graph.Nodes, UpgradeNode{Name: b.StartingCSV, Version: bundleVersion(b)})
+	}
+
+	seen := map[UpgradeEdge]bool{}
+	addEdge := func(from, to, kind string) {
+		edge := UpgradeEdge{From: from, To: to, Type: kind}
+		if from == "" || seen[edge] {
+			return
+		}
+		if !inChannel[from] {
+			// a channel may start by replacing a bundle of another channel
+			if !inPackage[from] {
+				graph.MissingReferences = append(graph.MissingReferences, fmt.Sprintf("%s %s %s", to, kind, from))
+			}
+			return
+		}
+		seen[edge] = true
+		graph.Edges = append(graph.Edges, edge)
+	}
+	for _, b := range bundles {
+		addEdge(b.Replaces, b.StartingCSV, UpgradeReplaces)
+		for _, skipped := range b.Skips {
+			addEdge(skipped, b.StartingCSV, UpgradeSkips)
+		}
+		if b.SkipRange == "" {
+			continue
+		}
+		skipRange, err := semver.ParseRange(b.SkipRange)
+		if err != nil {
+			graph.MissingReferences = append(graph.MissingReferences, fmt.Sprintf("%s has an invalid skipRange %q", b.StartingCSV, b.SkipRange))
+			continue
+		}
+		// only older bundles upgrade through a skipRange, newer ones in the range would be downgrades
+		owner, err := semver.ParseTolerant(bundleVersion(b))
+		if err != nil {
+			continue
+		}
+		for _, node := range graph.Nodes {
+			if version, err := semver.ParseTolerant(node.Version); err == nil && version.LT(owner) && skipRange(version) {
+				addEdge(node.Name, b.StartingCSV, UpgradeSkipRange)
+			}
+		}
+	}
+
+	upgrades := map[string][]string{}
+	downgrades := map[string][]string{}
+	for _, edge := range graph.Edges {
+		upgrades[edge.From] = append(upgrades[edge.From], edge.To)
+		downgrades[edge.To] = append(downgrades[edge.To], edge.From)
+	}
+
+	// the head is the latest bundle nothing upgrades from, the others are dead ends
+	for i := len(graph.Nodes) - 1; i >= 0; i-- {
+		name := graph.Nodes[i].Name
+		if len(upgrades[name]) > 0 {
+			continue
+		}
+		if graph.Head == "" {
+			graph.Head = name
+		} else {
+			graph.DeadEnds = append(graph.DeadEnds, name)
+		}
+	}
+	sort.Strings(graph.DeadEnds)
+
+	if len(graph.Nodes) > 0 {
+		fromTail := reachable(graph.Nodes[0].Name, upgrades)
+		toHead := reachable(graph.Head, downgrades)
+		for _, node := range graph.Nodes {
+			if !fromTail[node.Name] {
+				graph.Unreachable = append(graph.Unreachable, node.Name)
+			}
+			if graph.Head == "" || !toHead[node.Name] {
+				graph.CannotReachHead = append(graph.CannotReachHead, node.Name)
+			}
+		}
+	}
+
+	graph.Cycles = findCycles(graph.Nodes, upgrades)
+
+	return graph
+}
+
+// bundleVersion is the version of the CSV, or the version directory when the CSV has none
+func bundleVersion(b Bundle) string {
+	if b.CSV != nil && b.CSV.Spec.Version.String() != "0.0.0" {
+		return b.CSV.Spec.Version.String()
+	}
+	return b.Version
+}
+
+// reachable returns the nodes that can be reached from start, start included
+func reachable(start string, edges map[string][]string) map[string]bool {
+	visited := map[string]bool{}
+	if start == "" {
+		return visited
+	}
+	queue := []string{start}
+	visited[start] = true
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, next := range edges[node] {
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return visited
+}
+
+// findCycles returns the strongly connected components of more than one node, using Tarjan's algorithm
+func findCycles(nodes []UpgradeNode, edges map[string][]string) [][]string {
+	index := 0
+	indexes := map[string]int{}
+	lowlinks := map[string]int{}
+	onStack := map[string]bool{}
+	stack := []string{}
+	cycles := [][]string{}
+
+	var connect func(node string)
+	connect = func(node string) {
+		indexes[node] = index
+		lowlinks[node] = index
+		index++
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, next := range edges[node] {
+			if _, visited := indexes[next]; !visited {
+				connect(next)
+				if lowlinks[next] < lowlinks[node] {
+					lowlinks[node] = lowlinks[next]
+				}
+			} else if onStack[next] && indexes[next] < lowlinks[node] {
+				lowlinks[node] = indexes[next]
+			}
+		}
+
+		if lowlinks[node] != indexes[node] {
+			return
+		}
+		component := []string{}
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, last)
+			if last == node {
+				break
+			}
+		}
+		if len(component) > 1 {
+			sort.Strings(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	for _, node := range nodes {
+		if _, visited := indexes[node.Name]; !visited {
+			connect(node.Name)
+		}
+	}
+
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i][0] < cycles[j][0] })
+	return cycles
+}
+
+// DOT renders the graph in the Graphviz DOT language. The head is drawn in bold, dead ends and bundles that can't
+// reach the head in red.
+func (g UpgradeGraph) DOT() string {
+	deadEnds := map[string]bool{}
+	for _, name := range append(append([]string{}, g.DeadEnds...), g.CannotReachHead...) {
+		deadEnds[name] = true
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "digraph %q {\n", g.Package+"/"+g.Channel)
+	for _, node := range g.Nodes {
+		attributes := fmt.Sprintf("label=%q", node.Name+"\n"+node.Version)
+		switch {
+		case node.Name == g.Head:
+			attributes += ",style=bold"
+		case deadEnds[node.Name]:
+			attributes += ",color=red"
+		}
+		fmt.Fprintf(&sb, "  %q [%s];\n", node.Name, attributes)
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&sb, "  %q -> %q [label=%q];\n", edge.From, edge.To, edge.Type)
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
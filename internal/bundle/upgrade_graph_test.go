@@ -0,0 +1,81 @@
+package bundle
+
+import (
+	. "github.com/onsi/ginkgo/v2/dsl/core"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Upgrade graph", func() {
+	var graphs []UpgradeGraph
+
+	BeforeEach(func() {
+		bundles, err := ReadBundlesFromDir("testdata/upgrade-graph")
+		Expect(err).ToNot(HaveOccurred())
+		graphs = BuildUpgradeGraphs(bundles, "widget-operator")
+		Expect(graphs).To(HaveLen(2))
+	})
+
+	When("building the graph of a healthy channel", func() {
+		It("should find the head and no problems", func() {
+			stable := graphs[1]
+			Expect(stable.Channel).To(Equal("stable"))
+			Expect(stable.Head).To(Equal("widget-operator.v2.0.0"))
+			Expect(stable.Nodes).To(HaveLen(4))
+			Expect(stable.Nodes[0].Name).To(Equal("widget-operator.v1.0.0"))
+			Expect(stable.Edges).To(ContainElements(
+				UpgradeEdge{From: "widget-operator.v1.0.0", To: "widget-operator.v1.1.0", Type: UpgradeReplaces},
+				UpgradeEdge{From: "widget-operator.v1.0.0", To: "widget-operator.v2.0.0", Type: UpgradeSkipRange},
+			))
+			Expect(stable.DeadEnds).To(BeEmpty())
+			Expect(stable.Unreachable).To(BeEmpty())
+			Expect(stable.Cycles).To(BeEmpty())
+			Expect(stable.CannotReachHead).To(BeEmpty())
+			Expect(stable.MissingReferences).To(BeEmpty())
+		})
+	})
+
+	When("building the graph of a broken channel", func() {
+		It("should report its problems", func() {
+			fast := graphs[0]
+			Expect(fast.Channel).To(Equal("fast"))
+			Expect(fast.Head).To(Equal("widget-operator.v2.3.0"))
+			Expect(fast.DeadEnds).To(Equal([]string{"widget-operator.v2.0.1"}))
+			Expect(fast.Unreachable).To(Equal([]string{"widget-operator.v2.3.0"}))
+			Expect(fast.Cycles).To(Equal([][]string{{"widget-operator.v2.1.0", "widget-operator.v2.2.0"}}))
+			Expect(fast.CannotReachHead).To(HaveLen(5))
+			Expect(fast.MissingReferences).To(Equal([]string{"widget-operator.v2.3.0 replaces widget-operator.v0.9.0"}))
+		})
+	})
+
+	When("rendering the graph as DOT", func() {
+		It("should draw every node and edge", func() {
+			dot := graphs[1].DOT()
+			Expect(dot).To(HavePrefix(`digraph "widget-operator/stable" {`))
+			Expect(dot).To(ContainSubstring(`"widget-operator.v2.0.0" [label="widget-operator.v2.0.0\n2.0.0",style=bold];`))
+			Expect(dot).To(ContainSubstring(`"widget-operator.v1.2.0" -> "widget-operator.v2.0.0" [label="replaces"];`))
+		})
+	})
+
+	When("a skipRange goes past the version of its bundle", func() {
+		It("should only add edges from older bundles", func() {
+			graphs := BuildUpgradeGraphs([]Bundle{
+				{PackageName: "gizmo-operator", Channel: "stable", StartingCSV: "gizmo-operator.v1.0.0", Version: "1.0.0"},
+				{PackageName: "gizmo-operator", Channel: "stable", StartingCSV: "gizmo-operator.v1.1.0", Version: "1.1.0", SkipRange: ">=1.0.0 <2.0.0"},
+				{PackageName: "gizmo-operator", Channel: "stable", StartingCSV: "gizmo-operator.v1.2.0", Version: "1.2.0", Replaces: "gizmo-operator.v1.1.0"},
+			}, "gizmo-operator")
+			Expect(graphs).To(HaveLen(1))
+			Expect(graphs[0].Edges).To(ConsistOf(
+				UpgradeEdge{From: "gizmo-operator.v1.0.0", To: "gizmo-operator.v1.1.0", Type: UpgradeSkipRange},
+				UpgradeEdge{From: "gizmo-operator.v1.1.0", To: "gizmo-operator.v1.2.0", Type: UpgradeReplaces},
+			))
+			Expect(graphs[0].Head).To(Equal("gizmo-operator.v1.2.0"))
+			Expect(graphs[0].Cycles).To(BeEmpty())
+		})
+	})
+
+	When("the package has no bundles", func() {
+		It("should return no graph", func() {
+			Expect(BuildUpgradeGraphs(nil, "widget-operator")).To(BeEmpty())
+		})
+	})
+})
@@ -7,7 +7,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/opdev/opcap/internal/bundle"
+	"github.com/opdev/opcap/internal/logger"
 	"github.com/opdev/opcap/internal/operator"
+	"github.com/opdev/opcap/internal/report"
 	"github.com/spf13/afero"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
@@ -22,6 +25,12 @@ type capAudit struct {
 	// OpenShift Cluster Version under test
 	ocpVersion string
 
+	// OpenShift versions the bundle declares support for, empty when unknown
+	declaredOcpVersions string
+
+	// Bundle parsed from the bundles directory, nil when unknown
+	bundle *bundle.Bundle
+
 	// namespace is the ns where the operator will be installed
 	namespace string
 
@@ -212,13 +221,113 @@ func withDetailedReports(detailedReports bool) auditOption {
 	}
 }
 
+// withScaleFieldPath adds the path to a replica-like field of operands
+func withScaleFieldPath(scaleFieldPath string) auditOption {
+	return func(options *auditOptions) error {
+		options.scaleFieldPath = scaleFieldPath
+		return nil
+	}
+}
+
+// withAllowedRegistries adds the registries images may be pulled from
+func withAllowedRegistries(allowedRegistries []string) auditOption {
+	return func(options *auditOptions) error {
+		options.allowedRegistries = allowedRegistries
+		return nil
+	}
+}
+
+// withDeclaredOcpVersions adds the OpenShift versions the bundle declares support for
+func withDeclaredOcpVersions(declaredOcpVersions string) auditOption {
+	return func(options *auditOptions) error {
+		options.declaredOcpVersions = declaredOcpVersions
+		return nil
+	}
+}
+
+// withBundle adds the bundle under audit
+func withBundle(b *bundle.Bundle) auditOption {
+	return func(options *auditOptions) error {
+		options.bundle = b
+		return nil
+	}
+}
+
+// withAPIWarnings adds the warnings the API server sent during the audits of the package so far
+func withAPIWarnings(apiWarnings *[]string) auditOption {
+	return func(options *auditOptions) error {
+		options.apiWarnings = apiWarnings
+		return nil
+	}
+}
+
+// drainAPIWarnings returns the warnings the API server sent since they were last drained and adds them to the
+// warnings of the package
+func drainAPIWarnings(options auditOptions) []string {
+	warnings := options.client.Warnings()
+	if options.apiWarnings != nil {
+		*options.apiWarnings = append(*options.apiWarnings, warnings...)
+	}
+	return warnings
+}
+
+// ocpSupport tells whether the declared OpenShift versions include the version of the cluster
+func ocpSupport(declaredOcpVersions, ocpVersion string) string {
+	if declaredOcpVersions == "" {
+		return report.OcpSupportUnknown
+	}
+
+	versions, err := bundle.ParseOcpVersionRange(declaredOcpVersions)
+	if err != nil {
+		logger.Errorf("could not parse declared OpenShift versions: %v", err)
+		return report.OcpSupportUnknown
+	}
+	supported, err := versions.Includes(ocpVersion)
+	if err != nil {
+		logger.Errorf("could not compare OpenShift versions: %v", err)
+		return report.OcpSupportUnknown
+	}
+	if !supported {
+		return report.OcpUnsupported
+	}
+	return report.OcpSupported
+}
+
 // New returns a function corresponding to a passed in audit plan
 func newAudit(ctx context.Context, auditType string, opts ...auditOption) (auditFn, auditCleanupFn) {
 	switch strings.ToLower(auditType) {
 	case "operatorinstall":
 		return operatorInstall(ctx, opts...)
+	case "operatorresidue":
+		return operatorResidue(ctx, opts...)
 	case "operandinstall":
 		return operandInstall(ctx, opts...)
+	case "operanduninstall":
+		return operandUninstall(ctx, opts...)
+	case "operandscale":
+		return operandScale(ctx, opts...)
+	case "metrics":
+		return metrics(ctx, opts...)
+	case "prometheusrules":
+		return prometheusRules(ctx, opts...)
+	case "operatorevents":
+		return operatorEvents(ctx, opts...)
+	case "operandstatus":
+		return operandStatus(ctx, opts...)
+	case "operandadmission":
+		return operandAdmission(ctx, opts...)
+	case "crdcoverage":
+		return crdCoverage(ctx, opts...)
+	case "rbacprivilege":
+		return rbacPrivilege(ctx, opts...)
+	case "podsecurity":
+		return podSecurity(ctx, opts...)
+	case "imagehygiene":
+		return imageHygiene(ctx, opts...)
+	case "disconnectedreadiness":
+		return disconnectedReadiness(ctx, opts...)
+	case "deprecatedapis":
+		return deprecatedAPIs(ctx, opts...)
 	case "fakeplan":
 		return func(ctx context.Context) error { return nil }, func(ctx context.Context) error { return nil }
 	}
@@ -12,8 +12,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/opdev/opcap/internal/bundle"
 	"github.com/opdev/opcap/internal/logger"
 	"github.com/opdev/opcap/internal/operator"
+	"github.com/opdev/opcap/internal/report"
 	"github.com/spf13/afero"
 	"k8s.io/apimachinery/pkg/util/yaml"
 )
@@ -143,6 +145,15 @@ func buildWorkQueueByCatalog(ctx context.Context, options *auditorOptions, extra
 			return fmt.Errorf("could not build configuration for subscription: %s: %v", subscription.Name, err)
 		}
 
+		if b, ok := bundle.LatestBundle(options.bundles, subscription.Package, subscription.Channel); ok {
+			capAudit.declaredOcpVersions = b.OcpVersions
+			capAudit.bundle = &b
+		}
+		if options.skipUnsupported && ocpSupport(capAudit.declaredOcpVersions, capAudit.ocpVersion) == report.OcpUnsupported {
+			logger.Infow("skipping package not declaring support for the cluster OpenShift version", "package", subscription.Package, "ocpVersion", capAudit.ocpVersion, "declaredOcpVersions", capAudit.declaredOcpVersions)
+			continue
+		}
+
 		// load workqueue with capAudit
 		options.workQueue <- *capAudit
 	}
@@ -197,6 +208,10 @@ func RunAudits(ctx context.Context, opts ...auditorOption) error {
 
 	// read workqueue for audits
 	for audit := range options.workQueue {
+		// forget the warnings sent before this audit started
+		audit.client.Warnings()
+		apiWarnings := []string{}
+
 		// read a particular audit's auditPlan for functions
 		// to be executed against operator
 		for _, function := range audit.auditPlan {
@@ -213,6 +228,12 @@ func RunAudits(ctx context.Context, opts ...auditorOption) error {
 				withFilesystem(options.fs),
 				withReportWriter(options.reportWriter),
 				withDetailedReports(options.detailedReports),
+				withScaleFieldPath(options.scaleFieldPath),
+				withAllowedRegistries(options.allowedRegistries),
+				withOcpVersion(audit.ocpVersion),
+				withDeclaredOcpVersions(audit.declaredOcpVersions),
+				withBundle(audit.bundle),
+				withAPIWarnings(&apiWarnings),
 			)
 			if auditFn == nil {
 				logger.Errorf("invalid audit plan specified: %s", function)
@@ -220,6 +241,7 @@ func RunAudits(ctx context.Context, opts ...auditorOption) error {
 			}
 			cleanups.Push(auditCleanupFn)
 			err := auditFn(ctx)
+			apiWarnings = append(apiWarnings, audit.client.Warnings()...)
 			if err != nil {
 				logger.Errorf("error in audit: %v", err)
 				break
@@ -237,10 +259,20 @@ func WithAuditPlan(auditPlan []string) auditorOption {
 		if len(auditPlan) == 0 {
 			return fmt.Errorf("audit plan cannot be empty")
 		}
+		installed := false
 		for _, plan := range auditPlan {
 			if len(plan) == 0 {
 				return fmt.Errorf("audit plan incorrectly specified")
 			}
+			// the residue snapshot has to be taken before the operator is installed
+			switch strings.ToLower(plan) {
+			case "operatorinstall":
+				installed = true
+			case "operatorresidue":
+				if installed {
+					return fmt.Errorf("OperatorResidue must come before OperatorInstall in the audit plan")
+				}
+			}
 		}
 		options.auditPlan = auditPlan
 		return nil
@@ -325,3 +357,31 @@ func WithDetailedReports(detailedReports bool) auditorOption {
 		return nil
 	}
 }
+
+func WithScaleFieldPath(scaleFieldPath string) auditorOption {
+	return func(options *auditorOptions) error {
+		options.scaleFieldPath = scaleFieldPath
+		return nil
+	}
+}
+
+func WithAllowedRegistries(allowedRegistries []string) auditorOption {
+	return func(options *auditorOptions) error {
+		options.allowedRegistries = allowedRegistries
+		return nil
+	}
+}
+
+func WithBundles(bundles []bundle.Bundle) auditorOption {
+	return func(options *auditorOptions) error {
+		options.bundles = bundles
+		return nil
+	}
+}
+
+func WithSkipUnsupported(skipUnsupported bool) auditorOption {
+	return func(options *auditorOptions) error {
+		options.skipUnsupported = skipUnsupported
+		return nil
+	}
+}
@@ -104,6 +104,12 @@ var _ = Describe("Auditor tests", func() {
 					Expect(WithAuditPlan(([]string{""}))(options)).ToNot(Succeed())
 				})
 			})
+			When("OperatorResidue comes after OperatorInstall", func() {
+				It("should throw an error", func() {
+					Expect(WithAuditPlan([]string{"OperatorInstall", "OperatorResidue"})(options)).ToNot(Succeed())
+					Expect(WithAuditPlan([]string{"OperatorResidue", "OperatorInstall"})(options)).To(Succeed())
+				})
+			})
 		})
 		Context("catalogsource", func() {
 			When("catalogsource is supplied", func() {
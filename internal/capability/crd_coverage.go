@@ -0,0 +1,99 @@
+package capability
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/opdev/opcap/internal/logger"
+	"github.com/opdev/opcap/internal/report"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// crdCoverage compares the CRDs owned by the CSV with the kinds found in the alm-examples annotation and
+// in the extra CR directory. Owned CRDs with no example are never exercised by the operand audits.
+func crdCoverage(ctx context.Context, opts ...auditOption) (auditFn, auditCleanupFn) {
+	var options auditOptions
+	for _, opt := range opts {
+		err := opt(&options)
+		if err != nil {
+			return func(_ context.Context) error {
+					return fmt.Errorf("option failed: %v", err)
+				}, func(_ context.Context) error {
+					return nil
+				}
+		}
+	}
+
+	return func(ctx context.Context) error {
+		logger.Debugw("checking CRD coverage for operator", "package", options.subscription.Package, "channel", options.subscription.Channel, "installmode", options.subscription.InstallModeType)
+
+		csv, err := packageCSV(ctx, options)
+		if err != nil {
+			return fmt.Errorf("could not get CSV: %v", err)
+		}
+		if csv == nil {
+			return fmt.Errorf("exiting CRDCoverage since no CSV was found for package %s", options.subscription.Package)
+		}
+
+		examples, err := almExamples(csv)
+		if err != nil {
+			logger.Errorf("could not get ALM Examples: %v", err)
+		}
+
+		result := report.CRDCoverageResult{}
+		almKinds := exampleKinds(examples)
+		extraKinds := exampleKinds(options.customResources)
+		for _, owned := range csv.Spec.CustomResourceDefinitions.Owned {
+			gk := schema.GroupKind{Kind: owned.Kind}
+			if i := strings.Index(owned.Name, "."); i >= 0 {
+				gk.Group = owned.Name[i+1:]
+			}
+			coverage := report.CRDCoverage{
+				Name:          owned.Name,
+				Kind:          owned.Kind,
+				Version:       owned.Version,
+				InAlmExamples: almKinds[gk],
+				InExtraCRs:    extraKinds[gk],
+			}
+			if coverage.InAlmExamples || coverage.InExtraCRs {
+				result.Covered++
+			}
+			result.CRDs = append(result.CRDs, coverage)
+		}
+
+		file, err := options.fs.OpenFile("crd_coverage_report.json", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		data := report.TemplateData{
+			OcpVersion:   options.ocpVersion,
+			Subscription: *options.subscription,
+			CRDCoverage:  result,
+		}
+
+		if err := report.CRDCoverageJsonReport(file, data); err != nil {
+			return fmt.Errorf("could not generate CRD coverage JSON report: %v", err)
+		}
+
+		if err := report.CRDCoverageTextReport(options.reportWriter, data); err != nil {
+			return fmt.Errorf("could not generate CRD coverage text report: %v", err)
+		}
+
+		return nil
+	}, func(_ context.Context) error { return nil }
+}
+
+// exampleKinds returns the group and kind of every custom resource
+func exampleKinds(customResources []map[string]interface{}) map[schema.GroupKind]bool {
+	kinds := map[schema.GroupKind]bool{}
+	for _, cr := range customResources {
+		kinds[(&unstructured.Unstructured{Object: cr}).GroupVersionKind().GroupKind()] = true
+	}
+	return kinds
+}
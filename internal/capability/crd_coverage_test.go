@@ -0,0 +1,62 @@
+package capability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opdev/opcap/internal/operator"
+	operatorv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/spf13/afero"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("CRDCoverage audit", func() {
+	It("should report owned CRDs without an example", func() {
+		csv := &operatorv1alpha1.ClusterServiceVersion{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "testpackage.v1.0.0",
+				Namespace: "testns",
+				Annotations: map[string]string{
+					"alm-examples": `[{"apiVersion":"example.com/v1","kind":"Example","metadata":{"name":"example"}}]`,
+				},
+			},
+			Spec: operatorv1alpha1.ClusterServiceVersionSpec{
+				CustomResourceDefinitions: operatorv1alpha1.CustomResourceDefinitions{
+					Owned: []operatorv1alpha1.CRDDescription{
+						{Name: "examples.example.com", Kind: "Example", Version: "v1"},
+						{Name: "backups.example.com", Kind: "Backup", Version: "v1"},
+						{Name: "restores.example.com", Kind: "Restore", Version: "v1alpha1"},
+						{Name: "examples.other.com", Kind: "Example", Version: "v1"},
+					},
+				},
+			},
+		}
+
+		fs := afero.NewMemMapFs()
+		output := &bytes.Buffer{}
+		auditFn, _ := crdCoverage(context.TODO(),
+			withClient(operator.NewFakeOpClient(csv)),
+			withNamespace("testns"),
+			withSubscription(&operator.SubscriptionData{Package: "testpackage"}),
+			withCustomResources([]map[string]interface{}{
+				{"apiVersion": "example.com/v1", "kind": "Backup", "metadata": map[string]interface{}{"name": "backup"}},
+			}),
+			withFilesystem(fs),
+			withReportWriter(output),
+		)
+		Expect(auditFn(context.TODO())).To(Succeed())
+
+		Expect(output.String()).To(ContainSubstring("Owned CRDs: 4\nCovered CRDs: 2"))
+		Expect(output.String()).To(ContainSubstring("CRD: examples.example.com (Example v1): alm-examples\n"))
+		Expect(output.String()).To(ContainSubstring("CRD: backups.example.com (Backup v1): extra CRs\n"))
+		Expect(output.String()).To(ContainSubstring("CRD: restores.example.com (Restore v1alpha1): no example\n"))
+		Expect(output.String()).To(ContainSubstring("CRD: examples.other.com (Example v1): no example\n"))
+
+		report, err := afero.ReadFile(fs, "crd_coverage_report.json")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(json.Valid(report)).To(BeTrue())
+	})
+})
@@ -0,0 +1,300 @@
+package capability
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/opdev/opcap/internal/bundle"
+	"github.com/opdev/opcap/internal/logger"
+	"github.com/opdev/opcap/internal/report"
+	operatorv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// deprecatedAPI is an API version deprecated for some kinds, either by Kubernetes or by a CRD
+type deprecatedAPI struct {
+	apiVersion   string
+	kinds        []string
+	deprecatedIn string
+	removedIn    string
+	replacement  string
+}
+
+// deprecatedKubernetesAPIs are the Kubernetes API versions deprecated or removed since Kubernetes 1.16,
+// see https://kubernetes.io/docs/reference/using-api/deprecation-guide/
+var deprecatedKubernetesAPIs = []deprecatedAPI{
+	{"extensions/v1beta1", []string{"Deployment", "DaemonSet", "ReplicaSet"}, "1.9", "1.16", "apps/v1"},
+	{"extensions/v1beta1", []string{"NetworkPolicy"}, "1.9", "1.16", "networking.k8s.io/v1"},
+	{"extensions/v1beta1", []string{"PodSecurityPolicy"}, "1.10", "1.16", "policy/v1beta1"},
+	{"extensions/v1beta1", []string{"Ingress"}, "1.14", "1.22", "networking.k8s.io/v1"},
+	{"apps/v1beta1", []string{"Deployment", "StatefulSet", "ReplicaSet"}, "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta2", []string{"Deployment", "StatefulSet", "DaemonSet", "ReplicaSet"}, "1.9", "1.16", "apps/v1"},
+	{"apiextensions.k8s.io/v1beta1", []string{"CustomResourceDefinition"}, "1.16", "1.22", "apiextensions.k8s.io/v1"},
+	{"admissionregistration.k8s.io/v1beta1", []string{"MutatingWebhookConfiguration", "ValidatingWebhookConfiguration"}, "1.16", "1.22", "admissionregistration.k8s.io/v1"},
+	{"apiregistration.k8s.io/v1beta1", []string{"APIService"}, "1.19", "1.22", "apiregistration.k8s.io/v1"},
+	{"authentication.k8s.io/v1beta1", []string{"TokenReview"}, "1.19", "1.22", "authentication.k8s.io/v1"},
+	{"authorization.k8s.io/v1beta1", []string{"SubjectAccessReview", "LocalSubjectAccessReview", "SelfSubjectAccessReview"}, "1.19", "1.22", "authorization.k8s.io/v1"},
+	{"certificates.k8s.io/v1beta1", []string{"CertificateSigningRequest"}, "1.19", "1.22", "certificates.k8s.io/v1"},
+	{"coordination.k8s.io/v1beta1", []string{"Lease"}, "1.19", "1.22", "coordination.k8s.io/v1"},
+	{"networking.k8s.io/v1beta1", []string{"Ingress", "IngressClass"}, "1.19", "1.22", "networking.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", []string{"ClusterRole", "ClusterRoleBinding", "Role", "RoleBinding"}, "1.17", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"scheduling.k8s.io/v1beta1", []string{"PriorityClass"}, "1.14", "1.22", "scheduling.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", []string{"CSIDriver", "CSINode", "StorageClass", "VolumeAttachment"}, "1.19", "1.22", "storage.k8s.io/v1"},
+	{"batch/v1beta1", []string{"CronJob"}, "1.21", "1.25", "batch/v1"},
+	{"discovery.k8s.io/v1beta1", []string{"EndpointSlice"}, "1.21", "1.25", "discovery.k8s.io/v1"},
+	{"events.k8s.io/v1beta1", []string{"Event"}, "1.22", "1.25", "events.k8s.io/v1"},
+	{"autoscaling/v2beta1", []string{"HorizontalPodAutoscaler"}, "1.22", "1.25", "autoscaling/v2"},
+	{"policy/v1beta1", []string{"PodDisruptionBudget"}, "1.21", "1.25", "policy/v1"},
+	{"policy/v1beta1", []string{"PodSecurityPolicy"}, "1.21", "1.25", ""},
+	{"node.k8s.io/v1beta1", []string{"RuntimeClass"}, "1.20", "1.25", "node.k8s.io/v1"},
+	{"autoscaling/v2beta2", []string{"HorizontalPodAutoscaler"}, "1.23", "1.26", "autoscaling/v2"},
+	{"flowcontrol.apiserver.k8s.io/v1beta1", []string{"FlowSchema", "PriorityLevelConfiguration"}, "1.23", "1.26", "flowcontrol.apiserver.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", []string{"CSIStorageCapacity"}, "1.24", "1.27", "storage.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta2", []string{"FlowSchema", "PriorityLevelConfiguration"}, "1.26", "1.29", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta3", []string{"FlowSchema", "PriorityLevelConfiguration"}, "1.29", "1.32", "flowcontrol.apiserver.k8s.io/v1"},
+}
+
+// managedFieldsKinds are the kinds whose managed fields are checked on top of the secondary resources and operands.
+// Their current API version is listed, the managed fields record the version each manager wrote them with.
+var managedFieldsKinds = []schema.GroupVersionKind{
+	{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"},
+	{Group: "networking.k8s.io", Version: "v1", Kind: "NetworkPolicy"},
+	{Group: "policy", Version: "v1", Kind: "PodDisruptionBudget"},
+	{Group: "autoscaling", Version: "v1", Kind: "HorizontalPodAutoscaler"},
+	{Group: "batch", Version: "v1", Kind: "CronJob"},
+}
+
+// deprecatedAPIs looks for API versions deprecated or removed in upcoming Kubernetes versions, and for deprecated
+// CRD versions, in the bundle manifests, the ALM examples and the managed fields of the objects in the audit
+// namespaces, which record the API version every client wrote them with. The served versions of the CRDs owned
+// by the CSV that are marked deprecated are reported as well.
+func deprecatedAPIs(ctx context.Context, opts ...auditOption) (auditFn, auditCleanupFn) {
+	var options auditOptions
+	for _, opt := range opts {
+		err := opt(&options)
+		if err != nil {
+			return func(_ context.Context) error {
+					return fmt.Errorf("option failed: %v", err)
+				}, func(_ context.Context) error {
+					return nil
+				}
+		}
+	}
+
+	if err := extractAlmExamples(ctx, &options); err != nil {
+		logger.Errorf("could not get ALM Examples: %v", err)
+	}
+
+	return func(ctx context.Context) error {
+		logger.Debugw("looking for deprecated APIs used by operator", "package", options.subscription.Package, "channel", options.subscription.Channel, "installmode", options.subscription.InstallModeType)
+
+		csv, err := packageCSV(ctx, options)
+		if err != nil {
+			return fmt.Errorf("could not get CSV: %v", err)
+		}
+
+		crds := &apiextensionsv1.CustomResourceDefinitionList{}
+		if err := options.client.ListCRDs(ctx, crds); err != nil {
+			return fmt.Errorf("could not list CRDs: %v", err)
+		}
+		apis := append(deprecatedCRDVersions(crds.Items), deprecatedKubernetesAPIs...)
+
+		usages := ownedCRDUsages(csv, crds.Items)
+		if options.bundle != nil {
+			usages = append(usages, bundleManifestUsages(*options.bundle, apis)...)
+		}
+		for _, cr := range options.customResources {
+			obj := unstructured.Unstructured{Object: cr}
+			if usage, ok := deprecatedAPIUsage(apis, "custom resource", obj.GetAPIVersion(), obj.GetKind(), obj.GetName()); ok {
+				usages = append(usages, usage)
+			}
+		}
+		objectUsages, err := managedFieldsUsages(ctx, options, apis)
+		if err != nil {
+			return err
+		}
+		usages = append(usages, objectUsages...)
+
+		warnings := drainAPIWarnings(options)
+		if options.apiWarnings != nil {
+			warnings = *options.apiWarnings
+		}
+
+		file, err := options.fs.OpenFile("deprecated_apis_report.json", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		data := report.TemplateData{
+			OcpVersion:     options.ocpVersion,
+			Subscription:   *options.subscription,
+			DeprecatedAPIs: uniqueUsages(usages),
+			APIWarnings:    uniqueStrings(warnings),
+		}
+
+		if err := report.DeprecatedAPIsJsonReport(file, data); err != nil {
+			return fmt.Errorf("could not generate deprecated APIs JSON report: %v", err)
+		}
+
+		if err := report.DeprecatedAPIsTextReport(options.reportWriter, data); err != nil {
+			return fmt.Errorf("could not generate deprecated APIs text report: %v", err)
+		}
+
+		return nil
+	}, func(_ context.Context) error { return nil }
+}
+
+// deprecatedCRDVersions returns the served CRD versions marked deprecated, to be replaced by the storage version
+func deprecatedCRDVersions(crds []apiextensionsv1.CustomResourceDefinition) []deprecatedAPI {
+	apis := []deprecatedAPI{}
+	for _, crd := range crds {
+		storage := ""
+		for _, version := range crd.Spec.Versions {
+			if version.Storage && !version.Deprecated {
+				storage = crd.Spec.Group + "/" + version.Name
+			}
+		}
+		for _, version := range crd.Spec.Versions {
+			if version.Served && version.Deprecated {
+				apis = append(apis, deprecatedAPI{
+					apiVersion:  crd.Spec.Group + "/" + version.Name,
+					kinds:       []string{crd.Spec.Names.Kind},
+					replacement: storage,
+				})
+			}
+		}
+	}
+	return apis
+}
+
+// deprecatedAPIUsage checks an API version and kind against the deprecated APIs
+func deprecatedAPIUsage(apis []deprecatedAPI, source, apiVersion, kind, name string) (report.DeprecatedAPIUsage, bool) {
+	for _, api := range apis {
+		if api.apiVersion == apiVersion && contains(api.kinds, kind) {
+			return report.DeprecatedAPIUsage{
+				Source:       source,
+				APIVersion:   apiVersion,
+				Kind:         kind,
+				Name:         name,
+				DeprecatedIn: api.deprecatedIn,
+				RemovedIn:    api.removedIn,
+				Replacement:  api.replacement,
+			}, true
+		}
+	}
+	return report.DeprecatedAPIUsage{}, false
+}
+
+// ownedCRDUsages reports the deprecated versions still served by the CRDs the CSV owns
+func ownedCRDUsages(csv *operatorv1alpha1.ClusterServiceVersion, crds []apiextensionsv1.CustomResourceDefinition) []report.DeprecatedAPIUsage {
+	usages := []report.DeprecatedAPIUsage{}
+	if csv == nil {
+		return usages
+	}
+
+	for _, owned := range csv.Spec.CustomResourceDefinitions.Owned {
+		for _, crd := range crds {
+			if crd.Name != owned.Name {
+				continue
+			}
+			for _, api := range deprecatedCRDVersions([]apiextensionsv1.CustomResourceDefinition{crd}) {
+				usage, _ := deprecatedAPIUsage([]deprecatedAPI{api}, "CRD "+crd.Name, api.apiVersion, crd.Spec.Names.Kind, "")
+				usages = append(usages, usage)
+			}
+		}
+	}
+
+	return usages
+}
+
+// bundleManifestUsages checks every manifest of the bundle
+func bundleManifestUsages(b bundle.Bundle, apis []deprecatedAPI) []report.DeprecatedAPIUsage {
+	usages := []report.DeprecatedAPIUsage{}
+	for _, manifest := range b.Manifests {
+		if usage, ok := deprecatedAPIUsage(apis, "bundle manifest "+manifest.File, manifest.APIVersion, manifest.Kind, manifest.Name); ok {
+			usages = append(usages, usage)
+		}
+	}
+	return usages
+}
+
+// managedFieldsUsages checks the API version recorded by every manager of the objects in the audit namespaces,
+// which reveals the API versions the operator uses at runtime
+func managedFieldsUsages(ctx context.Context, options auditOptions, apis []deprecatedAPI) ([]report.DeprecatedAPIUsage, error) {
+	usages := []report.DeprecatedAPIUsage{}
+
+	kinds := append(append([]schema.GroupVersionKind{}, secondaryResourceKinds...), managedFieldsKinds...)
+	for _, cr := range options.customResources {
+		gvk := (&unstructured.Unstructured{Object: cr}).GroupVersionKind()
+		if gvk.Kind != "" && !containsKind(kinds, gvk) {
+			kinds = append(kinds, gvk)
+		}
+	}
+
+	for _, ns := range auditNamespaces(options) {
+		for _, gvk := range kinds {
+			items, err := listResources(ctx, options.client, gvk, ns)
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range items {
+				for _, entry := range item.GetManagedFields() {
+					source := "managed fields of " + entry.Manager
+					if usage, ok := deprecatedAPIUsage(apis, source, entry.APIVersion, item.GetKind(), ns+"/"+item.GetName()); ok {
+						usages = append(usages, usage)
+					}
+				}
+			}
+		}
+	}
+
+	return usages, nil
+}
+
+func containsKind(kinds []schema.GroupVersionKind, gvk schema.GroupVersionKind) bool {
+	for _, kind := range kinds {
+		if kind == gvk {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueStrings removes duplicates, keeping the first occurrence of every string
+func uniqueStrings(values []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+// uniqueUsages removes duplicate usages, e.g. a manager writing the same object several times, and sorts them
+func uniqueUsages(usages []report.DeprecatedAPIUsage) []report.DeprecatedAPIUsage {
+	seen := map[report.DeprecatedAPIUsage]bool{}
+	unique := []report.DeprecatedAPIUsage{}
+	for _, usage := range usages {
+		if !seen[usage] {
+			seen[usage] = true
+			unique = append(unique, usage)
+		}
+	}
+
+	sort.SliceStable(unique, func(i, j int) bool {
+		if unique[i].Source != unique[j].Source {
+			return unique[i].Source < unique[j].Source
+		}
+		return unique[i].Name < unique[j].Name
+	})
+	return unique
+}
@@ -0,0 +1,143 @@
+package capability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opdev/opcap/internal/bundle"
+	"github.com/opdev/opcap/internal/operator"
+	operatorv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/spf13/afero"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// warningClient answers every request with the same API server warnings
+type warningClient struct {
+	operator.Client
+	warnings []string
+}
+
+func (c warningClient) Warnings() []string {
+	return c.warnings
+}
+
+var _ = Describe("DeprecatedAPIs audit", func() {
+	crd := apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.com"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "example.com",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: "Widget", Plural: "widgets"},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: true, Deprecated: true},
+				{Name: "v1", Served: true, Storage: true},
+			},
+		},
+	}
+
+	Context("looking up APIs", func() {
+		apis := append(deprecatedCRDVersions([]apiextensionsv1.CustomResourceDefinition{crd}), deprecatedKubernetesAPIs...)
+
+		DescribeTable("matching API versions and kinds",
+			func(apiVersion, kind string, deprecated bool, replacement string) {
+				usage, ok := deprecatedAPIUsage(apis, "test", apiVersion, kind, "name")
+				Expect(ok).To(Equal(deprecated))
+				Expect(usage.Replacement).To(Equal(replacement))
+			},
+			Entry("removed Kubernetes API", "policy/v1beta1", "PodDisruptionBudget", true, "policy/v1"),
+			Entry("removed API without replacement", "policy/v1beta1", "PodSecurityPolicy", true, ""),
+			Entry("current Kubernetes API", "policy/v1", "PodDisruptionBudget", false, ""),
+			Entry("version deprecated for another kind", "batch/v1beta1", "Job", false, ""),
+			Entry("deprecated CRD version", "example.com/v1alpha1", "Widget", true, "example.com/v1"),
+			Entry("current CRD version", "example.com/v1", "Widget", false, ""),
+		)
+	})
+
+	Context("scanning bundle manifests", func() {
+		It("should check every manifest", func() {
+			b := bundle.Bundle{Manifests: []bundle.Manifest{
+				{File: "manifests/resources.yaml", APIVersion: "policy/v1beta1", Kind: "PodDisruptionBudget", Name: "operator-pdb"},
+				{File: "manifests/resources.yaml", APIVersion: "v1", Kind: "Service", Name: "operator-metrics"},
+				{File: "manifests/role.yaml", APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "ClusterRole", Name: "operator-role"},
+			}}
+
+			usages := bundleManifestUsages(b, deprecatedKubernetesAPIs)
+			Expect(usages).To(HaveLen(2))
+			Expect(usages[0].Source).To(Equal("bundle manifest manifests/resources.yaml"))
+			Expect(usages[0].Name).To(Equal("operator-pdb"))
+			Expect(usages[1].RemovedIn).To(Equal("1.22"))
+		})
+	})
+
+	Context("running the audit", func() {
+		It("should report deprecated CRD versions and managed fields", func() {
+			csv := &operatorv1alpha1.ClusterServiceVersion{
+				ObjectMeta: metav1.ObjectMeta{Name: "testpackage.v1.0.0", Namespace: "testns"},
+				Spec: operatorv1alpha1.ClusterServiceVersionSpec{
+					CustomResourceDefinitions: operatorv1alpha1.CustomResourceDefinitions{
+						Owned: []operatorv1alpha1.CRDDescription{{Name: "widgets.example.com", Kind: "Widget", Version: "v1"}},
+					},
+				},
+			}
+			widget := &unstructured.Unstructured{}
+			widget.SetAPIVersion("example.com/v1")
+			widget.SetKind("Widget")
+			widget.SetName("widget")
+			widget.SetNamespace("testns")
+			widget.SetManagedFields([]metav1.ManagedFieldsEntry{
+				{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationApply, APIVersion: "example.com/v1alpha1"},
+				{Manager: "operator", Operation: metav1.ManagedFieldsOperationUpdate, APIVersion: "example.com/v1"},
+			})
+
+			fs := afero.NewMemMapFs()
+			output := &bytes.Buffer{}
+			auditFn, _ := deprecatedAPIs(context.TODO(),
+				withClient(operator.NewFakeOpClient(csv, crd.DeepCopy(), widget)),
+				withNamespace("testns"),
+				withSubscription(&operator.SubscriptionData{Package: "testpackage"}),
+				withCustomResources([]map[string]interface{}{widget.Object}),
+				withFilesystem(fs),
+				withReportWriter(output),
+			)
+			Expect(auditFn(context.TODO())).To(Succeed())
+
+			Expect(output.String()).To(ContainSubstring("Deprecated API Usages: 2\n"))
+			Expect(output.String()).To(ContainSubstring("example.com/v1alpha1 Widget\n  Used by: CRD widgets.example.com\n"))
+			Expect(output.String()).To(ContainSubstring("example.com/v1alpha1 Widget testns/widget\n  Used by: managed fields of kubectl\n"))
+
+			report, err := afero.ReadFile(fs, "deprecated_apis_report.json")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(json.Valid(report)).To(BeTrue())
+		})
+
+		It("should report the API warnings of the package", func() {
+			apiWarnings := []string{`unknown field "spec.sise"`}
+			client := warningClient{
+				Client:   operator.NewFakeOpClient(),
+				warnings: []string{`unknown field "spec.sise"`, "example.com/v1alpha1 Widget is deprecated"},
+			}
+
+			fs := afero.NewMemMapFs()
+			output := &bytes.Buffer{}
+			auditFn, _ := deprecatedAPIs(context.TODO(),
+				withClient(client),
+				withNamespace("testns"),
+				withSubscription(&operator.SubscriptionData{Package: "testpackage"}),
+				withAPIWarnings(&apiWarnings),
+				withFilesystem(fs),
+				withReportWriter(output),
+			)
+			Expect(auditFn(context.TODO())).To(Succeed())
+
+			Expect(output.String()).To(ContainSubstring("API Warnings: 2\n  unknown field \"spec.sise\"\n  example.com/v1alpha1 Widget is deprecated\n"))
+
+			report, err := afero.ReadFile(fs, "deprecated_apis_report.json")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(json.Valid(report)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,132 @@
+package capability
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/opdev/opcap/internal/logger"
+	"github.com/opdev/opcap/internal/report"
+	operatorv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// infrastructureFeaturesAnnotation lists the infrastructure features of an operator as a JSON array
+	infrastructureFeaturesAnnotation = "operators.openshift.io/infrastructure-features"
+	// disconnectedFeatureAnnotation is the newer, one annotation per feature, way of declaring disconnected support
+	disconnectedFeatureAnnotation = "features.operators.openshift.io/disconnected"
+)
+
+// disconnectedReadiness determines whether an operator can be installed in a disconnected cluster: the CSV must
+// declare the disconnected infrastructure feature, every image it references must be pinned by digest and listed in
+// relatedImages so it can be mirrored, and the pods in the audit namespaces must not run any undeclared image.
+func disconnectedReadiness(ctx context.Context, opts ...auditOption) (auditFn, auditCleanupFn) {
+	var options auditOptions
+	for _, opt := range opts {
+		err := opt(&options)
+		if err != nil {
+			return func(_ context.Context) error {
+					return fmt.Errorf("option failed: %v", err)
+				}, func(_ context.Context) error {
+					return nil
+				}
+		}
+	}
+
+	return func(ctx context.Context) error {
+		logger.Debugw("checking disconnected readiness for operator", "package", options.subscription.Package, "channel", options.subscription.Channel, "installmode", options.subscription.InstallModeType)
+
+		csv, err := packageCSV(ctx, options)
+		if err != nil {
+			return fmt.Errorf("could not get CSV: %v", err)
+		}
+		if csv == nil {
+			return fmt.Errorf("exiting DisconnectedReadiness since no CSV was found for package %s", options.subscription.Package)
+		}
+
+		pods, err := auditPods(ctx, options)
+		if err != nil {
+			return err
+		}
+
+		file, err := options.fs.OpenFile("disconnected_readiness_report.json", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		data := report.TemplateData{
+			OcpVersion:   options.ocpVersion,
+			Subscription: *options.subscription,
+			Csv:          csv,
+			Disconnected: checkDisconnectedReadiness(csv, pods),
+		}
+
+		if err := report.DisconnectedReadinessJsonReport(file, data); err != nil {
+			return fmt.Errorf("could not generate disconnected readiness JSON report: %v", err)
+		}
+
+		if err := report.DisconnectedReadinessTextReport(options.reportWriter, data); err != nil {
+			return fmt.Errorf("could not generate disconnected readiness text report: %v", err)
+		}
+
+		return nil
+	}, func(_ context.Context) error { return nil }
+}
+
+func checkDisconnectedReadiness(csv *operatorv1alpha1.ClusterServiceVersion, pods []corev1.Pod) report.DisconnectedResult {
+	result := report.DisconnectedResult{
+		Declared: declaresDisconnected(csv),
+	}
+
+	// the CSV images are the ones mirrored before installing, the pod images the ones actually pulled
+	for _, check := range checkImages(csv, nil, nil) {
+		if !check.PinnedByDigest {
+			result.UnpinnedImages = append(result.UnpinnedImages, check.Image)
+		}
+		if !check.RelatedImage {
+			result.UndeclaredImages = append(result.UndeclaredImages, check.Image)
+		}
+	}
+	pulled := map[string]bool{}
+	for _, pod := range pods {
+		for _, container := range append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+			if container.Image != "" && !relatedImage(csv, container.Image) {
+				pulled[container.Image] = true
+			}
+		}
+	}
+	for image := range pulled {
+		result.RuntimeUndeclaredImages = append(result.RuntimeUndeclaredImages, image)
+	}
+	sort.Strings(result.RuntimeUndeclaredImages)
+
+	result.Ready = result.Declared && len(result.UnpinnedImages) == 0 && len(result.UndeclaredImages) == 0 && len(result.RuntimeUndeclaredImages) == 0
+
+	return result
+}
+
+// declaresDisconnected tells whether the CSV declares support for disconnected clusters, through either
+// the infrastructure-features list or the dedicated feature annotation
+func declaresDisconnected(csv *operatorv1alpha1.ClusterServiceVersion) bool {
+	if strings.EqualFold(csv.Annotations[disconnectedFeatureAnnotation], "true") {
+		return true
+	}
+
+	features := []string{}
+	if err := json.Unmarshal([]byte(csv.Annotations[infrastructureFeaturesAnnotation]), &features); err != nil {
+		return false
+	}
+	for _, feature := range features {
+		if strings.EqualFold(feature, "disconnected") {
+			return true
+		}
+	}
+
+	return false
+}
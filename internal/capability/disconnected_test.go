@@ -0,0 +1,99 @@
+package capability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opdev/opcap/internal/operator"
+	operatorv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/spf13/afero"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("DisconnectedReadiness audit", func() {
+	operatorImage := "quay.io/myorg/operator@" + testDigest
+
+	newCSV := func(annotations map[string]string, image string) *operatorv1alpha1.ClusterServiceVersion {
+		return &operatorv1alpha1.ClusterServiceVersion{
+			ObjectMeta: metav1.ObjectMeta{Name: "testpackage.v1.0.0", Namespace: "testns", Annotations: annotations},
+			Spec: operatorv1alpha1.ClusterServiceVersionSpec{
+				RelatedImages: []operatorv1alpha1.RelatedImage{{Name: "operator", Image: operatorImage}},
+				InstallStrategy: operatorv1alpha1.NamedInstallStrategy{
+					StrategySpec: operatorv1alpha1.StrategyDetailsDeployment{
+						DeploymentSpecs: []operatorv1alpha1.StrategyDeploymentSpec{{
+							Name: "operator",
+							Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{Name: "manager", Image: image}},
+							}}},
+						}},
+					},
+				},
+			},
+		}
+	}
+	newPod := func(image string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "operand-0", Namespace: "testns"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "operand", Image: image}}},
+		}
+	}
+
+	Context("declaring disconnected support", func() {
+		DescribeTable("reading the CSV annotations",
+			func(annotations map[string]string, expected bool) {
+				Expect(declaresDisconnected(newCSV(annotations, operatorImage))).To(Equal(expected))
+			},
+			Entry("infrastructure features", map[string]string{infrastructureFeaturesAnnotation: `["Disconnected", "proxy-aware"]`}, true),
+			Entry("feature annotation", map[string]string{disconnectedFeatureAnnotation: "true"}, true),
+			Entry("other features", map[string]string{infrastructureFeaturesAnnotation: `["proxy-aware"]`}, false),
+			Entry("malformed features", map[string]string{infrastructureFeaturesAnnotation: `disconnected`}, false),
+			Entry("no annotation", nil, false),
+		)
+	})
+
+	Context("checking readiness", func() {
+		It("should be ready when everything is pinned and declared", func() {
+			result := checkDisconnectedReadiness(
+				newCSV(map[string]string{disconnectedFeatureAnnotation: "true"}, operatorImage),
+				[]corev1.Pod{newPod(operatorImage)},
+			)
+			Expect(result.Ready).To(BeTrue())
+		})
+		It("should report unpinned, undeclared and pulled images", func() {
+			result := checkDisconnectedReadiness(
+				newCSV(map[string]string{disconnectedFeatureAnnotation: "true"}, "quay.io/myorg/operator:v1"),
+				[]corev1.Pod{newPod("docker.io/library/redis:7")},
+			)
+			Expect(result.Ready).To(BeFalse())
+			Expect(result.UnpinnedImages).To(ConsistOf("quay.io/myorg/operator:v1"))
+			Expect(result.UndeclaredImages).To(ConsistOf("quay.io/myorg/operator:v1"))
+			Expect(result.RuntimeUndeclaredImages).To(ConsistOf("docker.io/library/redis:7"))
+		})
+	})
+
+	Context("running the audit", func() {
+		It("should report the readiness", func() {
+			fs := afero.NewMemMapFs()
+			output := &bytes.Buffer{}
+			auditFn, _ := disconnectedReadiness(context.TODO(),
+				withClient(operator.NewFakeOpClient(newCSV(nil, operatorImage))),
+				withNamespace("testns"),
+				withSubscription(&operator.SubscriptionData{Package: "testpackage"}),
+				withFilesystem(fs),
+				withReportWriter(output),
+			)
+			Expect(auditFn(context.TODO())).To(Succeed())
+
+			Expect(output.String()).To(ContainSubstring("Disconnected Ready: false\nDeclares Disconnected: false\n---"))
+
+			report, err := afero.ReadFile(fs, "disconnected_readiness_report.json")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(json.Valid(report)).To(BeTrue())
+		})
+	})
+})
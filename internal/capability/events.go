@@ -0,0 +1,204 @@
+package capability
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/opdev/opcap/internal/logger"
+	"github.com/opdev/opcap/internal/report"
+	operatorv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var eventKind = schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Event"}
+
+// genericEventReasons are reasons that carry no information about what the operator actually did
+var genericEventReasons = map[string]bool{
+	"":        true,
+	"Unknown": true,
+	"Event":   true,
+	"Info":    true,
+	"Warning": true,
+	"Error":   true,
+}
+
+// operatorEvents gathers the events emitted by the operator, or about its operands, in the audit namespaces
+// and scores how meaningful they are. Events are kept for a while by the API server, so placing this audit
+// after OperandInstall, OperandScale or OperandUninstall covers what the operator reported during those.
+//
+// The score goes from 0 to 3:
+// 0: the operator emits no events
+// 1: the operator emits events
+// 2: every event has a specific reason and a message
+// 3: on top of that, the operator uses both Normal and Warning events
+func operatorEvents(ctx context.Context, opts ...auditOption) (auditFn, auditCleanupFn) {
+	var options auditOptions
+	for _, opt := range opts {
+		err := opt(&options)
+		if err != nil {
+			return func(_ context.Context) error {
+					return fmt.Errorf("option failed: %v", err)
+				}, func(_ context.Context) error {
+					return nil
+				}
+		}
+	}
+
+	if err := extractAlmExamples(ctx, &options); err != nil {
+		logger.Errorf("could not get ALM Examples: %v", err)
+	}
+
+	return func(ctx context.Context) error {
+		logger.Debugw("auditing events for operator", "package", options.subscription.Package, "channel", options.subscription.Channel, "installmode", options.subscription.InstallModeType)
+
+		csv, err := packageCSV(ctx, options)
+		if err != nil {
+			return fmt.Errorf("could not get CSV: %v", err)
+		}
+
+		events := []corev1.Event{}
+		for _, ns := range auditNamespaces(options) {
+			items, err := listResources(ctx, options.client, eventKind, ns)
+			if err != nil {
+				return err
+			}
+			for _, item := range items {
+				event := corev1.Event{}
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &event); err != nil {
+					logger.Errorf("could not convert event %s: %v", item.GetName(), err)
+					continue
+				}
+				events = append(events, event)
+			}
+		}
+
+		result := scoreEvents(filterOperatorEvents(events, operandKinds(options.customResources, csv), operatorComponents(options.subscription.Package, csv)))
+
+		file, err := options.fs.OpenFile("operator_events_report.json", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		data := report.TemplateData{
+			OcpVersion:     options.ocpVersion,
+			Subscription:   *options.subscription,
+			OperatorEvents: result,
+		}
+
+		if err := report.OperatorEventsJsonReport(file, data); err != nil {
+			return fmt.Errorf("could not generate operator events JSON report: %v", err)
+		}
+
+		if err := report.OperatorEventsTextReport(options.reportWriter, data); err != nil {
+			return fmt.Errorf("could not generate operator events text report: %v", err)
+		}
+
+		return nil
+	}, func(_ context.Context) error { return nil }
+}
+
+// operandKinds returns the kinds of the operands, from the custom resources and the CRDs owned by the CSV
+func operandKinds(customResources []map[string]interface{}, csv *operatorv1alpha1.ClusterServiceVersion) map[string]bool {
+	kinds := map[string]bool{}
+	for _, cr := range customResources {
+		kinds[(&unstructured.Unstructured{Object: cr}).GetKind()] = true
+	}
+	if csv != nil {
+		for _, crd := range csv.Spec.CustomResourceDefinitions.Owned {
+			kinds[crd.Kind] = true
+		}
+	}
+	delete(kinds, "")
+	return kinds
+}
+
+// operatorComponents returns the names an operator is expected to report events under: its package
+// and the names of its deployments
+func operatorComponents(packageName string, csv *operatorv1alpha1.ClusterServiceVersion) []string {
+	components := []string{packageName}
+	if csv != nil {
+		for _, deployment := range csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs {
+			components = append(components, deployment.Name)
+		}
+	}
+	return components
+}
+
+// filterOperatorEvents keeps the events about an operand or reported by one of the operator components
+func filterOperatorEvents(events []corev1.Event, kinds map[string]bool, components []string) []corev1.Event {
+	filtered := []corev1.Event{}
+
+	for _, event := range events {
+		if kinds[event.InvolvedObject.Kind] {
+			filtered = append(filtered, event)
+			continue
+		}
+		for _, reporter := range []string{event.Source.Component, event.ReportingController} {
+			if reporter != "" && matchesComponent(reporter, components) {
+				filtered = append(filtered, event)
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].CreationTimestamp.Before(&filtered[j].CreationTimestamp)
+	})
+
+	return filtered
+}
+
+func matchesComponent(reporter string, components []string) bool {
+	for _, component := range components {
+		if component != "" && strings.Contains(reporter, component) {
+			return true
+		}
+	}
+	return false
+}
+
+func scoreEvents(events []corev1.Event) report.OperatorEventsResult {
+	result := report.OperatorEventsResult{}
+
+	meaningful := true
+	for _, event := range events {
+		result.Events = append(result.Events, report.Event{
+			InvolvedObjName:   event.InvolvedObject.Name,
+			InvolvedObjkind:   event.InvolvedObject.Kind,
+			CreationTimestamp: event.CreationTimestamp,
+			Message:           strings.Replace(event.Message, "\"", "", -1),
+			Reason:            event.Reason,
+			Type:              event.Type,
+		})
+		switch event.Type {
+		case corev1.EventTypeNormal:
+			result.Normal++
+		case corev1.EventTypeWarning:
+			result.Warning++
+		}
+		if genericEventReasons[event.Reason] || strings.TrimSpace(event.Message) == "" {
+			meaningful = false
+		}
+	}
+
+	if len(events) == 0 {
+		return result
+	}
+	result.Score = 1
+	if meaningful {
+		result.Score = 2
+		if result.Normal > 0 && result.Warning > 0 {
+			result.Score = 3
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,80 @@
+package capability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opdev/opcap/internal/operator"
+	"github.com/spf13/afero"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("OperatorEvents audit", func() {
+	newEvent := func(name, kind, component, eventType, reason, message string) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "testns"},
+			InvolvedObject: corev1.ObjectReference{Kind: kind, Name: "example"},
+			Source:         corev1.EventSource{Component: component},
+			Type:           eventType,
+			Reason:         reason,
+			Message:        message,
+		}
+	}
+
+	Context("scoring events", func() {
+		It("should score 0 without events", func() {
+			Expect(scoreEvents(nil).Score).To(Equal(0))
+		})
+		It("should score 1 with generic events", func() {
+			result := scoreEvents([]corev1.Event{*newEvent("a", "Example", "", corev1.EventTypeNormal, "Info", "")})
+			Expect(result.Score).To(Equal(1))
+		})
+		It("should score 2 with meaningful Normal events only", func() {
+			result := scoreEvents([]corev1.Event{*newEvent("a", "Example", "", corev1.EventTypeNormal, "Reconciled", "example reconciled")})
+			Expect(result.Score).To(Equal(2))
+		})
+		It("should score 3 with meaningful Normal and Warning events", func() {
+			result := scoreEvents([]corev1.Event{
+				*newEvent("a", "Example", "", corev1.EventTypeNormal, "Reconciled", "example reconciled"),
+				*newEvent("b", "Example", "", corev1.EventTypeWarning, "InvalidSpec", "size must be positive"),
+			})
+			Expect(result.Score).To(Equal(3))
+			Expect(result.Normal).To(Equal(1))
+			Expect(result.Warning).To(Equal(1))
+		})
+	})
+
+	Context("running the audit", func() {
+		It("should only report events about operands or from the operator", func() {
+			fs := afero.NewMemMapFs()
+			output := &bytes.Buffer{}
+			auditFn, _ := operatorEvents(context.TODO(),
+				withClient(operator.NewFakeOpClient(
+					newEvent("operand", "Example", "", corev1.EventTypeNormal, "Created", "example created"),
+					newEvent("operator", "Deployment", "testpackage-controller", corev1.EventTypeWarning, "ReconcileFailed", "could not reconcile"),
+					newEvent("kubelet", "Pod", "kubelet", corev1.EventTypeNormal, "Pulled", "image pulled"),
+				)),
+				withNamespace("testns"),
+				withSubscription(&operator.SubscriptionData{Package: "testpackage"}),
+				withCustomResources([]map[string]interface{}{{"apiVersion": "example.com/v1", "kind": "Example"}}),
+				withFilesystem(fs),
+				withReportWriter(output),
+			)
+			Expect(auditFn(context.TODO())).To(Succeed())
+
+			Expect(output.String()).To(ContainSubstring("Normal Events: 1"))
+			Expect(output.String()).To(ContainSubstring("Warning Events: 1"))
+			Expect(output.String()).To(ContainSubstring("Score: 3/3"))
+			Expect(output.String()).To(ContainSubstring("Event: Warning ReconcileFailed Deployment/example: could not reconcile"))
+			Expect(output.String()).ToNot(ContainSubstring("Pulled"))
+
+			report, err := afero.ReadFile(fs, "operator_events_report.json")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(json.Valid(report)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,197 @@
+package capability
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/opdev/opcap/internal/logger"
+	"github.com/opdev/opcap/internal/report"
+	operatorv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// defaultRegistry is where references without a registry host are pulled from
+const defaultRegistry = "docker.io"
+
+// imageReference is a container image reference split into its parts
+type imageReference struct {
+	registry   string
+	repository string
+	tag        string
+	digest     string
+}
+
+// imageHygiene checks the images referenced by the CSV and by the pods running in the audit namespaces. It flags
+// images referenced by tag instead of digest, images missing from relatedImages, which breaks mirroring for
+// disconnected clusters, and images pulled from registries outside of the allow-list.
+func imageHygiene(ctx context.Context, opts ...auditOption) (auditFn, auditCleanupFn) {
+	var options auditOptions
+	for _, opt := range opts {
+		err := opt(&options)
+		if err != nil {
+			return func(_ context.Context) error {
+					return fmt.Errorf("option failed: %v", err)
+				}, func(_ context.Context) error {
+					return nil
+				}
+		}
+	}
+
+	return func(ctx context.Context) error {
+		logger.Debugw("checking image references for operator", "package", options.subscription.Package, "channel", options.subscription.Channel, "installmode", options.subscription.InstallModeType)
+
+		csv, err := packageCSV(ctx, options)
+		if err != nil {
+			return fmt.Errorf("could not get CSV: %v", err)
+		}
+
+		pods, err := auditPods(ctx, options)
+		if err != nil {
+			return err
+		}
+
+		file, err := options.fs.OpenFile("image_hygiene_report.json", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		data := report.TemplateData{
+			OcpVersion:   options.ocpVersion,
+			Subscription: *options.subscription,
+			ImageChecks:  checkImages(csv, pods, options.allowedRegistries),
+		}
+
+		if err := report.ImageHygieneJsonReport(file, data); err != nil {
+			return fmt.Errorf("could not generate image hygiene JSON report: %v", err)
+		}
+
+		if err := report.ImageHygieneTextReport(options.reportWriter, data); err != nil {
+			return fmt.Errorf("could not generate image hygiene text report: %v", err)
+		}
+
+		return nil
+	}, func(_ context.Context) error { return nil }
+}
+
+// parseImageReference splits an image reference such as quay.io/org/image:tag@sha256:... into its parts.
+// References without a registry host default to docker.io and references without a tag or digest to latest.
+func parseImageReference(image string) imageReference {
+	ref := imageReference{}
+
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		ref.digest = name[i+1:]
+		name = name[:i]
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		ref.tag = name[i+1:]
+		name = name[:i]
+	}
+	if ref.tag == "" && ref.digest == "" {
+		ref.tag = "latest"
+	}
+
+	ref.registry = defaultRegistry
+	ref.repository = name
+	if i := strings.Index(name, "/"); i >= 0 {
+		host := name[:i]
+		if strings.ContainsAny(host, ".:") || host == "localhost" {
+			ref.registry = host
+			ref.repository = name[i+1:]
+		}
+	}
+
+	return ref
+}
+
+// imageSources maps every image referenced by the CSV deployments and the pods in the audit namespaces to where it
+// was found. Images only listed in relatedImages are included too.
+func imageSources(csv *operatorv1alpha1.ClusterServiceVersion, pods []corev1.Pod) map[string][]string {
+	sources := map[string][]string{}
+	add := func(image, source string) {
+		for _, s := range sources[image] {
+			if s == source {
+				return
+			}
+		}
+		sources[image] = append(sources[image], source)
+	}
+	addContainers := func(source string, spec corev1.PodSpec) {
+		for _, container := range append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...) {
+			add(container.Image, source)
+		}
+	}
+
+	if csv != nil {
+		for _, related := range csv.Spec.RelatedImages {
+			add(related.Image, "relatedImages/"+related.Name)
+		}
+		for _, deployment := range csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs {
+			addContainers("CSV Deployment/"+deployment.Name, deployment.Spec.Template.Spec)
+		}
+	}
+	for _, pod := range pods {
+		addContainers("Pod/"+pod.Namespace+"/"+pod.Name, pod.Spec)
+	}
+
+	return sources
+}
+
+// relatedImage tells whether an image is declared in the relatedImages of the CSV, either verbatim or by digest
+func relatedImage(csv *operatorv1alpha1.ClusterServiceVersion, image string) bool {
+	if csv == nil {
+		return false
+	}
+	digest := parseImageReference(image).digest
+	for _, related := range csv.Spec.RelatedImages {
+		if related.Image == image || (digest != "" && parseImageReference(related.Image).digest == digest) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowedRegistry tells whether an image comes from one of the allowed registries. An allowed registry can be a
+// host, e.g. registry.redhat.io, or a host and a repository prefix, e.g. quay.io/myorg. No allow-list allows everything.
+func allowedRegistry(image string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	ref := parseImageReference(image)
+	for _, registry := range allowed {
+		registry = strings.TrimSuffix(registry, "/")
+		if ref.registry == registry || strings.HasPrefix(ref.registry+"/"+ref.repository, registry+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkImages checks every image of the operator and its operands
+func checkImages(csv *operatorv1alpha1.ClusterServiceVersion, pods []corev1.Pod, allowed []string) []report.ImageCheck {
+	checks := []report.ImageCheck{}
+
+	for image, sources := range imageSources(csv, pods) {
+		if image == "" {
+			continue
+		}
+		ref := parseImageReference(image)
+		sort.Strings(sources)
+		checks = append(checks, report.ImageCheck{
+			Image:           image,
+			Sources:         sources,
+			Registry:        ref.registry,
+			PinnedByDigest:  ref.digest != "",
+			RelatedImage:    relatedImage(csv, image),
+			AllowedRegistry: allowedRegistry(image, allowed),
+		})
+	}
+
+	sort.Slice(checks, func(i, j int) bool { return checks[i].Image < checks[j].Image })
+	return checks
+}
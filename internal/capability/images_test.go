@@ -0,0 +1,92 @@
+package capability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opdev/opcap/internal/operator"
+	operatorv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/spf13/afero"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+var _ = Describe("ImageHygiene audit", func() {
+	Context("parsing image references", func() {
+		DescribeTable("splitting references",
+			func(image string, expected imageReference) {
+				Expect(parseImageReference(image)).To(Equal(expected))
+			},
+			Entry("registry, tag and digest", "quay.io/org/operator:v1.0@"+testDigest,
+				imageReference{registry: "quay.io", repository: "org/operator", tag: "v1.0", digest: testDigest}),
+			Entry("registry with port", "localhost:5000/operator:v1",
+				imageReference{registry: "localhost:5000", repository: "operator", tag: "v1"}),
+			Entry("docker hub", "library/busybox",
+				imageReference{registry: "docker.io", repository: "library/busybox", tag: "latest"}),
+			Entry("digest only", "registry.redhat.io/ubi8/ubi@"+testDigest,
+				imageReference{registry: "registry.redhat.io", repository: "ubi8/ubi", digest: testDigest}),
+		)
+	})
+
+	Context("allowing registries", func() {
+		It("should match hosts and repository prefixes", func() {
+			allowed := []string{"registry.redhat.io", "quay.io/myorg/"}
+			Expect(allowedRegistry("registry.redhat.io/ubi8/ubi:8.6", allowed)).To(BeTrue())
+			Expect(allowedRegistry("quay.io/myorg/operator:v1", allowed)).To(BeTrue())
+			Expect(allowedRegistry("quay.io/myorganization/operator:v1", allowed)).To(BeFalse())
+			Expect(allowedRegistry("busybox", allowed)).To(BeFalse())
+			Expect(allowedRegistry("busybox", nil)).To(BeTrue())
+		})
+	})
+
+	Context("running the audit", func() {
+		It("should report every image", func() {
+			csv := &operatorv1alpha1.ClusterServiceVersion{
+				ObjectMeta: metav1.ObjectMeta{Name: "testpackage.v1.0.0", Namespace: "testns"},
+				Spec: operatorv1alpha1.ClusterServiceVersionSpec{
+					RelatedImages: []operatorv1alpha1.RelatedImage{{Name: "operator", Image: "quay.io/myorg/operator@" + testDigest}},
+					InstallStrategy: operatorv1alpha1.NamedInstallStrategy{
+						StrategySpec: operatorv1alpha1.StrategyDetailsDeployment{
+							DeploymentSpecs: []operatorv1alpha1.StrategyDeploymentSpec{{
+								Name: "operator",
+								Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+									Containers: []corev1.Container{{Name: "manager", Image: "quay.io/myorg/operator@" + testDigest}},
+								}}},
+							}},
+						},
+					},
+				},
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "operand-0", Namespace: "testns"},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "operand", Image: "docker.io/library/redis:7"}}},
+			}
+
+			fs := afero.NewMemMapFs()
+			output := &bytes.Buffer{}
+			auditFn, _ := imageHygiene(context.TODO(),
+				withClient(operator.NewFakeOpClient(csv, pod)),
+				withNamespace("testns"),
+				withSubscription(&operator.SubscriptionData{Package: "testpackage"}),
+				withAllowedRegistries([]string{"quay.io/myorg"}),
+				withFilesystem(fs),
+				withReportWriter(output),
+			)
+			Expect(auditFn(context.TODO())).To(Succeed())
+
+			Expect(output.String()).To(ContainSubstring("Images: 2"))
+			Expect(output.String()).To(ContainSubstring("Image: docker.io/library/redis:7\n  Referenced by: Pod/testns/operand-0\n  Pinned by Digest: false\n  In relatedImages: false\n  Allowed Registry: false (docker.io)"))
+			Expect(output.String()).To(ContainSubstring("Image: quay.io/myorg/operator@" + testDigest + "\n  Referenced by: CSV Deployment/operator, relatedImages/operator\n  Pinned by Digest: true\n  In relatedImages: true\n  Allowed Registry: true (quay.io)"))
+
+			report, err := afero.ReadFile(fs, "image_hygiene_report.json")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(json.Valid(report)).To(BeTrue())
+		})
+	})
+})
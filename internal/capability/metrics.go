@@ -0,0 +1,350 @@
+package capability
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/opdev/opcap/internal/logger"
+	"github.com/opdev/opcap/internal/operator"
+	"github.com/opdev/opcap/internal/report"
+
+	"github.com/prometheus/common/expfmt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	serviceMonitorKind = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+	podMonitorKind     = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PodMonitor"}
+	serviceKind        = schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Service"}
+	podKind            = schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Pod"}
+)
+
+const defaultMetricsPath = "/metrics"
+
+// metricsTarget is an endpoint to be scraped through the API server proxy
+type metricsTarget struct {
+	// source is the ServiceMonitor, PodMonitor or Service, as Kind/name, the target was discovered from
+	source string
+	// proxyKind is either "services" or "pods"
+	proxyKind string
+	namespace string
+	name      string
+	scheme    string
+	port      string
+	path      string
+}
+
+// metricsScraper fetches the raw metrics of a target
+type metricsScraper func(ctx context.Context, target metricsTarget) ([]byte, error)
+
+// metrics looks for the Services, ServiceMonitors and PodMonitors created by the operator or its operands,
+// scrapes the discovered metrics endpoints and checks that they serve the Prometheus text format
+func metrics(ctx context.Context, opts ...auditOption) (auditFn, auditCleanupFn) {
+	var options auditOptions
+	for _, opt := range opts {
+		err := opt(&options)
+		if err != nil {
+			return func(_ context.Context) error {
+					return fmt.Errorf("option failed: %v", err)
+				}, func(_ context.Context) error {
+					return nil
+				}
+		}
+	}
+
+	return func(ctx context.Context) error {
+		logger.Debugw("auditing metrics for operator", "package", options.subscription.Package, "channel", options.subscription.Channel, "installmode", options.subscription.InstallModeType)
+
+		c, err := k8sClientset()
+		if err != nil {
+			return fmt.Errorf("couldn't get clientset for metrics audit: %v", err)
+		}
+		scrape := func(ctx context.Context, target metricsTarget) ([]byte, error) {
+			if target.proxyKind == "pods" {
+				return c.CoreV1().Pods(target.namespace).ProxyGet(target.scheme, target.name, target.port, target.path, nil).DoRaw(ctx)
+			}
+			return c.CoreV1().Services(target.namespace).ProxyGet(target.scheme, target.name, target.port, target.path, nil).DoRaw(ctx)
+		}
+
+		return auditMetrics(ctx, options, scrape)
+	}, func(_ context.Context) error { return nil }
+}
+
+func auditMetrics(ctx context.Context, options auditOptions, scrape metricsScraper) error {
+	result := report.MetricsResult{}
+	targets := []metricsTarget{}
+
+	for _, ns := range auditNamespaces(options) {
+		nsTargets, monitors, err := discoverMetricsTargets(ctx, options.client, ns)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, nsTargets...)
+		result.Monitors = append(result.Monitors, monitors...)
+	}
+
+	for _, target := range targets {
+		endpoint := report.MetricsEndpoint{
+			Source:    target.source,
+			Namespace: target.namespace,
+			Target:    target.proxyKind + "/" + target.name + ":" + target.port + target.path,
+		}
+
+		data, err := scrape(ctx, target)
+		if err != nil {
+			endpoint.Message = fmt.Sprintf("could not scrape endpoint: %v", err)
+			result.Endpoints = append(result.Endpoints, endpoint)
+			continue
+		}
+		endpoint.Scraped = true
+
+		families, err := parseMetricFamilies(data)
+		if err != nil {
+			endpoint.Message = fmt.Sprintf("invalid Prometheus text format: %v", err)
+			result.Endpoints = append(result.Endpoints, endpoint)
+			continue
+		}
+		endpoint.Valid = true
+		endpoint.MetricFamilies = families
+		result.Endpoints = append(result.Endpoints, endpoint)
+	}
+
+	file, err := options.fs.OpenFile("metrics_report.json", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	data := report.TemplateData{
+		OcpVersion:   options.ocpVersion,
+		Subscription: *options.subscription,
+		Metrics:      result,
+	}
+
+	if err := report.MetricsJsonReport(file, data); err != nil {
+		return fmt.Errorf("could not generate metrics JSON report: %v", err)
+	}
+
+	if err := report.MetricsTextReport(options.reportWriter, data); err != nil {
+		return fmt.Errorf("could not generate metrics text report: %v", err)
+	}
+
+	return nil
+}
+
+// discoverMetricsTargets finds the metrics endpoints of a namespace. Endpoints declared by ServiceMonitors and
+// PodMonitors come first; Services exposing a port named after metrics that no monitor selects are added too.
+func discoverMetricsTargets(ctx context.Context, client operator.Client, namespace string) ([]metricsTarget, []string, error) {
+	targets := []metricsTarget{}
+	monitors := []string{}
+	monitored := map[string]bool{}
+
+	services, err := listResources(ctx, client, serviceKind, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serviceMonitors, err := listResources(ctx, client, serviceMonitorKind, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, sm := range serviceMonitors {
+		monitors = append(monitors, resourceName(sm))
+		selector, err := monitorSelector(sm)
+		if err != nil {
+			logger.Errorf("invalid selector on %s: %v", resourceName(sm), err)
+			continue
+		}
+		endpoints, _, _ := unstructured.NestedSlice(sm.Object, "spec", "endpoints")
+		for _, svc := range services {
+			if !selector.Matches(labels.Set(svc.GetLabels())) {
+				continue
+			}
+			monitored[svc.GetName()] = true
+			for _, e := range endpoints {
+				endpoint, ok := e.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				target := newMetricsTarget(resourceName(sm), "services", svc.GetNamespace(), svc.GetName(), endpoint)
+				if _, ok, _ := unstructured.NestedString(endpoint, "port"); !ok {
+					// The service proxy only understands service ports, a targetPort is a port of the pods
+					target.port = servicePort(svc, target.port)
+				}
+				targets = append(targets, target)
+			}
+		}
+	}
+
+	podMonitors, err := listResources(ctx, client, podMonitorKind, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(podMonitors) > 0 {
+		pods, err := listResources(ctx, client, podKind, namespace)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, pm := range podMonitors {
+			monitors = append(monitors, resourceName(pm))
+			selector, err := monitorSelector(pm)
+			if err != nil {
+				logger.Errorf("invalid selector on %s: %v", resourceName(pm), err)
+				continue
+			}
+			endpoints, _, _ := unstructured.NestedSlice(pm.Object, "spec", "podMetricsEndpoints")
+			for _, pod := range pods {
+				if !selector.Matches(labels.Set(pod.GetLabels())) {
+					continue
+				}
+				for _, e := range endpoints {
+					endpoint, ok := e.(map[string]interface{})
+					if !ok {
+						continue
+					}
+					target := newMetricsTarget(resourceName(pm), "pods", pod.GetNamespace(), pod.GetName(), endpoint)
+					// The pod proxy only understands port numbers
+					target.port = containerPort(pod, target.port)
+					targets = append(targets, target)
+				}
+			}
+		}
+	}
+
+	for _, svc := range services {
+		if monitored[svc.GetName()] {
+			continue
+		}
+		ports, _, _ := unstructured.NestedSlice(svc.Object, "spec", "ports")
+		for _, p := range ports {
+			port, ok := p.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(port, "name")
+			if !strings.Contains(name, "metrics") {
+				continue
+			}
+			scheme := "http"
+			if strings.Contains(name, "https") {
+				scheme = "https"
+			}
+			targets = append(targets, newMetricsTarget(resourceName(svc), "services", svc.GetNamespace(), svc.GetName(), map[string]interface{}{
+				"port":   name,
+				"scheme": scheme,
+			}))
+		}
+	}
+
+	return targets, monitors, nil
+}
+
+func newMetricsTarget(source, proxyKind, namespace, name string, endpoint map[string]interface{}) metricsTarget {
+	target := metricsTarget{
+		source:    source,
+		proxyKind: proxyKind,
+		namespace: namespace,
+		name:      name,
+		scheme:    "http",
+		path:      defaultMetricsPath,
+	}
+
+	if port, ok, _ := unstructured.NestedString(endpoint, "port"); ok {
+		target.port = port
+	} else if port, ok, _ := unstructured.NestedFieldNoCopy(endpoint, "targetPort"); ok {
+		target.port = fmt.Sprint(port)
+	}
+	if scheme, ok, _ := unstructured.NestedString(endpoint, "scheme"); ok && scheme != "" {
+		target.scheme = scheme
+	}
+	if path, ok, _ := unstructured.NestedString(endpoint, "path"); ok && path != "" {
+		target.path = path
+	}
+
+	return target
+}
+
+func monitorSelector(monitor unstructured.Unstructured) (labels.Selector, error) {
+	raw, _, err := unstructured.NestedMap(monitor.Object, "spec", "selector")
+	if err != nil {
+		return nil, err
+	}
+	selector := &metav1.LabelSelector{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, selector); err != nil {
+		return nil, err
+	}
+	return metav1.LabelSelectorAsSelector(selector)
+}
+
+// containerPort resolves a named container port to its number, leaving numbers untouched
+func containerPort(pod unstructured.Unstructured, port string) string {
+	if _, err := strconv.Atoi(port); err == nil {
+		return port
+	}
+
+	typed := &corev1.Pod{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(pod.Object, typed); err != nil {
+		return port
+	}
+	for _, container := range typed.Spec.Containers {
+		for _, p := range container.Ports {
+			if p.Name == port {
+				return strconv.Itoa(int(p.ContainerPort))
+			}
+		}
+	}
+
+	return port
+}
+
+// servicePort resolves a port of the pods behind a Service, by name or number, to the name of the service port
+// forwarding to it, or its number when it has no name
+func servicePort(svc unstructured.Unstructured, targetPort string) string {
+	typed := &corev1.Service{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(svc.Object, typed); err != nil {
+		return targetPort
+	}
+	for _, p := range typed.Spec.Ports {
+		target := p.TargetPort.String()
+		// an unset targetPort defaults to the service port
+		if p.TargetPort.IntValue() == 0 && p.TargetPort.StrVal == "" {
+			target = strconv.Itoa(int(p.Port))
+		}
+		if target != targetPort {
+			continue
+		}
+		if p.Name != "" {
+			return p.Name
+		}
+		return strconv.Itoa(int(p.Port))
+	}
+
+	return targetPort
+}
+
+// parseMetricFamilies parses a scrape in the Prometheus text format and returns the sorted metric family names
+func parseMetricFamilies(data []byte) ([]string, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
@@ -0,0 +1,142 @@
+package capability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opdev/opcap/internal/operator"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("Metrics audit", func() {
+	newObject := func(apiVersion, kind, name string, labels map[string]string, spec map[string]interface{}) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+		obj.SetAPIVersion(apiVersion)
+		obj.SetKind(kind)
+		obj.SetName(name)
+		obj.SetNamespace("testns")
+		obj.SetLabels(labels)
+		return obj
+	}
+
+	var client operator.Client
+
+	BeforeEach(func() {
+		client = operator.NewFakeOpClient(
+			newObject("v1", "Service", "operator-metrics", map[string]string{"app": "operator"}, map[string]interface{}{
+				"ports": []interface{}{map[string]interface{}{"name": "https", "port": int64(8443)}},
+			}),
+			newObject("v1", "Service", "operand-metrics", nil, map[string]interface{}{
+				"ports": []interface{}{map[string]interface{}{"name": "metrics", "port": int64(9090)}},
+			}),
+			newObject("v1", "Service", "operand-web", nil, map[string]interface{}{
+				"ports": []interface{}{map[string]interface{}{"name": "web", "port": int64(80)}},
+			}),
+			newObject("monitoring.coreos.com/v1", "ServiceMonitor", "operator", nil, map[string]interface{}{
+				"selector":  map[string]interface{}{"matchLabels": map[string]interface{}{"app": "operator"}},
+				"endpoints": []interface{}{map[string]interface{}{"port": "https", "scheme": "https", "path": "/custom"}},
+			}),
+			newObject("monitoring.coreos.com/v1", "PodMonitor", "operand", nil, map[string]interface{}{
+				"selector":            map[string]interface{}{"matchLabels": map[string]interface{}{"app": "operand"}},
+				"podMetricsEndpoints": []interface{}{map[string]interface{}{"port": "prom"}},
+			}),
+			newObject("v1", "Pod", "operand-0", map[string]string{"app": "operand"}, map[string]interface{}{
+				"containers": []interface{}{map[string]interface{}{
+					"name":  "operand",
+					"ports": []interface{}{map[string]interface{}{"name": "prom", "containerPort": int64(9100)}},
+				}},
+			}),
+		)
+	})
+
+	Context("discovering targets", func() {
+		It("should find monitored and metrics-named endpoints", func() {
+			targets, monitors, err := discoverMetricsTargets(context.TODO(), client, "testns")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(monitors).To(ConsistOf("ServiceMonitor/operator", "PodMonitor/operand"))
+			Expect(targets).To(ConsistOf(
+				metricsTarget{source: "ServiceMonitor/operator", proxyKind: "services", namespace: "testns", name: "operator-metrics", scheme: "https", port: "https", path: "/custom"},
+				metricsTarget{source: "PodMonitor/operand", proxyKind: "pods", namespace: "testns", name: "operand-0", scheme: "http", port: "9100", path: "/metrics"},
+				metricsTarget{source: "Service/operand-metrics", proxyKind: "services", namespace: "testns", name: "operand-metrics", scheme: "http", port: "metrics", path: "/metrics"},
+			))
+		})
+	})
+
+	Context("discovering targets selected by their targetPort", func() {
+		It("should resolve the service ports forwarding to them", func() {
+			client = operator.NewFakeOpClient(
+				newObject("v1", "Service", "operand-api", map[string]string{"app": "operand"}, map[string]interface{}{
+					"ports": []interface{}{
+						map[string]interface{}{"name": "api", "port": int64(8080), "targetPort": "http-metrics"},
+						map[string]interface{}{"port": int64(9091), "targetPort": int64(9100)},
+						map[string]interface{}{"port": int64(7000)},
+					},
+				}),
+				newObject("monitoring.coreos.com/v1", "ServiceMonitor", "operand", nil, map[string]interface{}{
+					"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "operand"}},
+					"endpoints": []interface{}{
+						map[string]interface{}{"targetPort": "http-metrics"},
+						map[string]interface{}{"targetPort": int64(9100)},
+						map[string]interface{}{"targetPort": int64(7000)},
+					},
+				}),
+			)
+			targets, _, err := discoverMetricsTargets(context.TODO(), client, "testns")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(targets).To(ConsistOf(
+				metricsTarget{source: "ServiceMonitor/operand", proxyKind: "services", namespace: "testns", name: "operand-api", scheme: "http", port: "api", path: "/metrics"},
+				metricsTarget{source: "ServiceMonitor/operand", proxyKind: "services", namespace: "testns", name: "operand-api", scheme: "http", port: "9091", path: "/metrics"},
+				metricsTarget{source: "ServiceMonitor/operand", proxyKind: "services", namespace: "testns", name: "operand-api", scheme: "http", port: "7000", path: "/metrics"},
+			))
+		})
+	})
+
+	Context("parsing metrics", func() {
+		It("should return the metric families", func() {
+			families, err := parseMetricFamilies([]byte("# TYPE up gauge\nup 1\nreconcile_total{controller=\"x\"} 3\n"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(families).To(Equal([]string{"reconcile_total", "up"}))
+		})
+		It("should fail on anything else", func() {
+			_, err := parseMetricFamilies([]byte("<html>not metrics</html>"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("running the audit", func() {
+		It("should report every endpoint", func() {
+			fs := afero.NewMemMapFs()
+			output := &bytes.Buffer{}
+			scrape := func(_ context.Context, target metricsTarget) ([]byte, error) {
+				switch target.name {
+				case "operator-metrics":
+					return []byte("controller_runtime_reconcile_total 1\n"), nil
+				case "operand-0":
+					return []byte("<html></html>"), nil
+				}
+				return nil, fmt.Errorf("connection refused")
+			}
+			options := auditOptions{
+				client:       client,
+				namespace:    "testns",
+				subscription: &operator.SubscriptionData{Package: "testpackage"},
+				fs:           fs,
+				reportWriter: output,
+			}
+
+			Expect(auditMetrics(context.TODO(), options, scrape)).To(Succeed())
+			Expect(output.String()).To(ContainSubstring("Endpoint: testns/services/operator-metrics:https/custom (from ServiceMonitor/operator)\n  Scraped: true\n  Valid: true\n  Metric Families: 1"))
+			Expect(output.String()).To(ContainSubstring("Endpoint: testns/pods/operand-0:9100/metrics (from PodMonitor/operand)\n  Scraped: true\n  Valid: false"))
+			Expect(output.String()).To(ContainSubstring("could not scrape endpoint: connection refused"))
+
+			report, err := afero.ReadFile(fs, "metrics_report.json")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(json.Valid(report)).To(BeTrue())
+		})
+	})
+})
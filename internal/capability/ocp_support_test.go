@@ -0,0 +1,35 @@
+package capability
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opdev/opcap/internal/report"
+	operatorv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+)
+
+var _ = Describe("OpenShift version support", func() {
+	DescribeTable("comparing declared versions with the cluster version",
+		func(declared, ocpVersion, expected string) {
+			Expect(ocpSupport(declared, ocpVersion)).To(Equal(expected))
+		},
+		Entry("no declared versions", "", "4.11", report.OcpSupportUnknown),
+		Entry("unknown cluster version", "v4.8", "", report.OcpSupportUnknown),
+		Entry("invalid declared versions", "latest", "4.11", report.OcpSupportUnknown),
+		Entry("version and later", "v4.8", "4.11.5", report.OcpSupported),
+		Entry("range excluding the cluster", "v4.6-v4.8", "4.11", report.OcpUnsupported),
+		Entry("single version", "=v4.11", "4.11", report.OcpSupported),
+	)
+
+	DescribeTable("comparing the install outcome with the declared support",
+		func(support string, phase operatorv1alpha1.ClusterServiceVersionPhase, timeout bool, mismatch bool) {
+			csv := &operatorv1alpha1.ClusterServiceVersion{Status: operatorv1alpha1.ClusterServiceVersionStatus{Phase: phase}}
+			Expect(ocpSupportMismatch(support, csv, timeout) != "").To(Equal(mismatch))
+		},
+		Entry("supported and installed", report.OcpSupported, operatorv1alpha1.CSVPhaseSucceeded, false, false),
+		Entry("supported but failed", report.OcpSupported, operatorv1alpha1.CSVPhaseFailed, false, true),
+		Entry("supported but timed out", report.OcpSupported, operatorv1alpha1.CSVPhaseInstalling, true, true),
+		Entry("unsupported but installed", report.OcpUnsupported, operatorv1alpha1.CSVPhaseSucceeded, false, true),
+		Entry("unsupported and failed", report.OcpUnsupported, operatorv1alpha1.CSVPhaseFailed, false, false),
+		Entry("unknown support", report.OcpSupportUnknown, operatorv1alpha1.CSVPhaseFailed, false, false),
+	)
+})
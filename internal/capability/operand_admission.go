@@ -0,0 +1,245 @@
+package capability
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/opdev/opcap/internal/logger"
+	"github.com/opdev/opcap/internal/report"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// maxInvalidInputs caps the number of invalid variants submitted for a single ALM example
+const maxInvalidInputs = 50
+
+// invalidInput is an ALM example broken on purpose
+type invalidInput struct {
+	field       string
+	description string
+	obj         map[string]interface{}
+}
+
+// operandAdmission submits deliberately invalid variants of every ALM example and checks that the API server,
+// through the CRD schema or the operator's validating webhook, rejects them. Errors that are neither a schema
+// rejection nor a webhook denial, an unreachable webhook for instance, make the input inconclusive. The variants are derived from the
+// CRD OpenAPI schema: wrong types, missing required fields, values outside of an enum or a range.
+// Any variant that gets accepted is deleted right away.
+func operandAdmission(ctx context.Context, opts ...auditOption) (auditFn, auditCleanupFn) {
+	var options auditOptions
+	for _, opt := range opts {
+		err := opt(&options)
+		if err != nil {
+			return func(_ context.Context) error {
+					return fmt.Errorf("option failed: %v", err)
+				}, func(_ context.Context) error {
+					return nil
+				}
+		}
+	}
+
+	if err := extractAlmExamples(ctx, &options); err != nil {
+		logger.Errorf("could not get ALM Examples: %v", err)
+	}
+
+	return func(ctx context.Context) error {
+		logger.Debugw("testing operand admission for operator", "package", options.subscription.Package, "channel", options.subscription.Channel, "installmode", options.subscription.InstallModeType)
+
+		if len(options.customResources) == 0 {
+			logger.Infow("exiting OperandAdmission since no ALM_Examples found in CSV")
+			return nil
+		}
+
+		results := []report.OperandAdmissionResult{}
+		for i, cr := range options.customResources {
+			result, err := testOperandAdmission(ctx, options, &unstructured.Unstructured{Object: cr}, i)
+			if err != nil {
+				logger.Errorw("could not test operand admission", "error", err, "namespace", options.namespace)
+				continue
+			}
+			results = append(results, result)
+		}
+
+		file, err := options.fs.OpenFile("operand_admission_report.json", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		data := report.TemplateData{
+			OcpVersion:              options.ocpVersion,
+			Subscription:            *options.subscription,
+			OperandAdmissionResults: results,
+		}
+
+		if err := report.OperandAdmissionJsonReport(file, data); err != nil {
+			return fmt.Errorf("could not generate operand admission JSON report: %v", err)
+		}
+
+		if err := report.OperandAdmissionTextReport(options.reportWriter, data); err != nil {
+			return fmt.Errorf("could not generate operand admission text report: %v", err)
+		}
+
+		return nil
+	}, func(_ context.Context) error { return nil }
+}
+
+// testOperandAdmission creates every invalid variant of a single ALM example and records how the API server answered
+func testOperandAdmission(ctx context.Context, options auditOptions, cr *unstructured.Unstructured, index int) (report.OperandAdmissionResult, error) {
+	result := report.OperandAdmissionResult{
+		Kind: cr.GetKind(),
+		Name: cr.GetName(),
+	}
+
+	crd, err := crdForKind(ctx, options.client, cr.GroupVersionKind())
+	if err != nil {
+		return result, err
+	}
+	if crd == nil {
+		result.Message = "no CRD found for operand"
+		return result, nil
+	}
+	openAPISchema := crdSchema(crd, cr.GroupVersionKind().Version)
+	if openAPISchema == nil {
+		result.Message = "CRD has no schema for version " + cr.GroupVersionKind().Version
+		return result, nil
+	}
+
+	for i, input := range invalidInputs(cr.Object, openAPISchema) {
+		obj := &unstructured.Unstructured{Object: input.obj}
+		obj.SetNamespace(options.namespace)
+		obj.SetName(fmt.Sprintf("opcap-invalid-%d-%d", index, i))
+		obj.SetResourceVersion("")
+
+		admission := report.AdmissionInput{
+			Field:       input.field,
+			Description: input.description,
+		}
+
+		err := options.client.CreateUnstructured(ctx, obj)
+		switch {
+		case err == nil:
+			if err := options.client.DeleteUnstructured(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+				logger.Errorw("could not delete accepted invalid operand", "error", err, "name", obj.GetName())
+			}
+			result.Accepted++
+		case apierrors.IsAlreadyExists(err) || apierrors.IsConflict(err):
+			logger.Errorw("could not submit invalid operand", "error", err, "name", obj.GetName())
+			continue
+		case strings.Contains(err.Error(), "denied the request"):
+			admission.Rejected = true
+			admission.RejectedBy = "webhook"
+			admission.Message = err.Error()
+		case apierrors.IsInvalid(err) || apierrors.IsBadRequest(err):
+			admission.Rejected = true
+			admission.RejectedBy = "schema"
+			admission.Message = err.Error()
+		default:
+			// an unreachable webhook, a timeout or missing permissions tell nothing about the validation
+			admission.Inconclusive = true
+			admission.Message = err.Error()
+			result.Inconclusive++
+		}
+		result.Inputs = append(result.Inputs, admission)
+	}
+
+	return result, nil
+}
+
+// invalidInputs derives invalid variants of an ALM example from the schema of its CRD. Only the fields set in the
+// example are broken, plus the required ones, so that every variant is otherwise a valid operand.
+func invalidInputs(example map[string]interface{}, openAPISchema *apiextensionsv1.JSONSchemaProps) []invalidInput {
+	inputs := []invalidInput{}
+	collectInvalidInputs(example, openAPISchema, nil, &inputs)
+	if len(inputs) > maxInvalidInputs {
+		inputs = inputs[:maxInvalidInputs]
+	}
+	return inputs
+}
+
+func collectInvalidInputs(example map[string]interface{}, props *apiextensionsv1.JSONSchemaProps, path []string, inputs *[]invalidInput) {
+	variant := func(field []string, description string, value interface{}, remove bool) {
+		obj := runtime.DeepCopyJSON(example)
+		if remove {
+			unstructured.RemoveNestedField(obj, field...)
+		} else if err := unstructured.SetNestedField(obj, value, field...); err != nil {
+			return
+		}
+		*inputs = append(*inputs, invalidInput{field: strings.Join(field, "."), description: description, obj: obj})
+	}
+
+	for _, required := range props.Required {
+		field := append(append([]string{}, path...), required)
+		if len(path) == 0 && isObjectField(required) {
+			continue
+		}
+		variant(field, "missing required field", nil, true)
+	}
+
+	names := make([]string, 0, len(props.Properties))
+	for name := range props.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if len(path) == 0 && (isObjectField(name) || name == "status") {
+			continue
+		}
+		prop := props.Properties[name]
+		field := append(append([]string{}, path...), name)
+		value, found, _ := unstructured.NestedFieldNoCopy(example, field...)
+		if !found {
+			continue
+		}
+
+		if prop.XIntOrString || (prop.XPreserveUnknownFields != nil && *prop.XPreserveUnknownFields) {
+			continue
+		}
+		if wrong, ok := wrongTypeValue(prop.Type); ok {
+			variant(field, "wrong type, expected "+prop.Type, wrong, false)
+		}
+		if len(prop.Enum) > 0 && prop.Type == "string" {
+			variant(field, "value outside of enum", "opcap-invalid-value", false)
+		}
+		if prop.Maximum != nil {
+			variant(field, "value above maximum", outOfRange(prop.Type, *prop.Maximum+1), false)
+		}
+		if prop.Minimum != nil {
+			variant(field, "value below minimum", outOfRange(prop.Type, *prop.Minimum-1), false)
+		}
+
+		if _, ok := value.(map[string]interface{}); ok && prop.Type == "object" {
+			collectInvalidInputs(example, &prop, field, inputs)
+		}
+	}
+}
+
+// isObjectField tells whether a top-level field belongs to the object itself rather than to the operand
+func isObjectField(name string) bool {
+	return name == "apiVersion" || name == "kind" || name == "metadata"
+}
+
+// wrongTypeValue returns a value of a different type than the one the schema expects
+func wrongTypeValue(schemaType string) (interface{}, bool) {
+	switch schemaType {
+	case "string":
+		return int64(12345), true
+	case "integer", "number", "boolean", "array", "object":
+		return "opcap-invalid-value", true
+	}
+	return nil, false
+}
+
+func outOfRange(schemaType string, value float64) interface{} {
+	if schemaType == "integer" {
+		return int64(value)
+	}
+	return value
+}
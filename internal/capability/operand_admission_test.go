@@ -0,0 +1,169 @@
+package capability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opdev/opcap/internal/operator"
+	"github.com/spf13/afero"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// validatingClient rejects operands whose spec.size is not an integer, as a CRD schema would
+type validatingClient struct {
+	operator.Client
+}
+
+func (c validatingClient) CreateUnstructured(ctx context.Context, obj *unstructured.Unstructured) error {
+	if _, ok, err := unstructured.NestedInt64(obj.Object, "spec", "size"); !ok || err != nil {
+		return apierrors.NewInvalid(schema.GroupKind{Group: "example.com", Kind: "Example"}, obj.GetName(),
+			field.ErrorList{field.Invalid(field.NewPath("spec", "size"), nil, "must be an integer")})
+	}
+	return c.Client.CreateUnstructured(ctx, obj)
+}
+
+// failingClient fails every operand creation with the same error
+type failingClient struct {
+	operator.Client
+	err error
+}
+
+func (c failingClient) CreateUnstructured(_ context.Context, _ *unstructured.Unstructured) error {
+	return c.err
+}
+
+var _ = Describe("OperandAdmission audit", func() {
+	minimum := float64(1)
+	openAPISchema := &apiextensionsv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"apiVersion": {Type: "string"},
+			"kind":       {Type: "string"},
+			"metadata":   {Type: "object"},
+			"spec": {
+				Type:     "object",
+				Required: []string{"size"},
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"size":    {Type: "integer", Minimum: &minimum},
+					"mode":    {Type: "string", Enum: []apiextensionsv1.JSON{{Raw: []byte(`"fast"`)}}},
+					"version": {Type: "string"},
+				},
+			},
+		},
+	}
+	example := map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Example",
+		"metadata":   map[string]interface{}{"name": "example"},
+		"spec":       map[string]interface{}{"size": int64(3), "mode": "fast"},
+	}
+
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "examples.example.com"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "example.com",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: "Example", Plural: "examples"},
+			Scope: apiextensionsv1.NamespaceScoped,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+				Name:    "v1",
+				Served:  true,
+				Storage: true,
+				Schema:  &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: openAPISchema},
+			}},
+		},
+	}
+
+	Context("deriving invalid inputs", func() {
+		It("should break the example fields and the required ones", func() {
+			inputs := invalidInputs(example, openAPISchema)
+			descriptions := []string{}
+			for _, input := range inputs {
+				descriptions = append(descriptions, input.field+": "+input.description)
+			}
+			Expect(descriptions).To(Equal([]string{
+				"spec: wrong type, expected object",
+				"spec.size: missing required field",
+				"spec.mode: wrong type, expected string",
+				"spec.mode: value outside of enum",
+				"spec.size: wrong type, expected integer",
+				"spec.size: value below minimum",
+			}))
+			Expect(inputs[5].obj["spec"]).To(HaveKeyWithValue("size", int64(0)))
+			Expect(example["spec"]).To(HaveKeyWithValue("size", int64(3)))
+		})
+	})
+
+	Context("running the audit", func() {
+		It("should report the accepted invalid inputs", func() {
+			fs := afero.NewMemMapFs()
+			output := &bytes.Buffer{}
+			client := validatingClient{operator.NewFakeOpClient(crd)}
+			auditFn, _ := operandAdmission(context.TODO(),
+				withClient(client),
+				withNamespace("testns"),
+				withSubscription(&operator.SubscriptionData{Package: "testpackage"}),
+				withCustomResources([]map[string]interface{}{example}),
+				withFilesystem(fs),
+				withReportWriter(output),
+			)
+			Expect(auditFn(context.TODO())).To(Succeed())
+
+			Expect(output.String()).To(ContainSubstring("Invalid Inputs: 6\nAccepted Invalid Inputs: 3"))
+			Expect(output.String()).To(ContainSubstring("Rejected by schema: spec.size (missing required field)"))
+			Expect(output.String()).To(ContainSubstring("ACCEPTED: spec.mode (value outside of enum)"))
+
+			remaining := &unstructured.UnstructuredList{}
+			remaining.SetAPIVersion("example.com/v1")
+			remaining.SetKind("ExampleList")
+			Expect(client.ListUnstructured(context.TODO(), remaining, "testns")).To(Succeed())
+			Expect(remaining.Items).To(BeEmpty())
+
+			report, err := afero.ReadFile(fs, "operand_admission_report.json")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(json.Valid(report)).To(BeTrue())
+		})
+
+		DescribeTable("classifying the API server answers",
+			func(err error, rejectedBy string, inconclusive bool) {
+				options := auditOptions{client: failingClient{operator.NewFakeOpClient(crd), err}, namespace: "testns"}
+				result, testErr := testOperandAdmission(context.TODO(), options, &unstructured.Unstructured{Object: example}, 0)
+				Expect(testErr).ToNot(HaveOccurred())
+				Expect(result.Inputs).To(HaveLen(6))
+				Expect(result.Accepted).To(BeZero())
+				for _, input := range result.Inputs {
+					Expect(input.Rejected).To(Equal(!inconclusive))
+					Expect(input.RejectedBy).To(Equal(rejectedBy))
+					Expect(input.Inconclusive).To(Equal(inconclusive))
+				}
+				if inconclusive {
+					Expect(result.Inconclusive).To(Equal(6))
+				} else {
+					Expect(result.Inconclusive).To(BeZero())
+				}
+			},
+			Entry("schema rejection",
+				apierrors.NewInvalid(schema.GroupKind{Group: "example.com", Kind: "Example"}, "example", field.ErrorList{field.Required(field.NewPath("spec", "size"), "")}),
+				"schema", false),
+			Entry("webhook denial",
+				&apierrors.StatusError{ErrStatus: metav1.Status{Status: metav1.StatusFailure, Code: 403, Reason: metav1.StatusReasonForbidden,
+					Message: `admission webhook "vexample.example.com" denied the request: size must be positive`}},
+				"webhook", false),
+			Entry("unreachable webhook",
+				apierrors.NewInternalError(errors.New(`failed calling webhook "vexample.example.com": Post "https://example-webhook.testns.svc:443/validate": dial tcp 10.0.0.1:443: connect: connection refused`)),
+				"", true),
+			Entry("forbidden",
+				apierrors.NewForbidden(schema.GroupResource{Group: "example.com", Resource: "examples"}, "example", errors.New("user cannot create resource")),
+				"", true),
+			Entry("timeout", apierrors.NewTimeoutError("request timed out", 1), "", true),
+		)
+	})
+})
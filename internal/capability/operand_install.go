@@ -9,27 +9,19 @@ import (
 
 	"github.com/opdev/opcap/internal/logger"
 	"github.com/opdev/opcap/internal/report"
+	operatorv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/yaml"
 )
 
 func extractAlmExamples(ctx context.Context, options *auditOptions) error {
-	// gets the list of CSVs present in a particular namespace
-	csvList, err := options.client.ListClusterServiceVersions(ctx, options.namespace)
+	csv, err := packageCSV(ctx, *options)
 	if err != nil {
 		return err
 	}
-	almExamples := ""
-	for _, csvVal := range csvList.Items {
-		if strings.HasPrefix(csvVal.ObjectMeta.Name, options.subscription.Package) {
-			// map of string interface which consist of ALM examples from the CSVList
-			almExamples = csvVal.ObjectMeta.Annotations["alm-examples"]
-		}
-	}
-	var almList []map[string]interface{}
 
-	err = yaml.Unmarshal([]byte(almExamples), &almList)
+	almList, err := almExamples(csv)
 	if err != nil {
 		return err
 	}
@@ -39,6 +31,22 @@ func extractAlmExamples(ctx context.Context, options *auditOptions) error {
 	return nil
 }
 
+// almExamples parses the alm-examples annotation of a CSV. A nil CSV has no examples.
+func almExamples(csv *operatorv1alpha1.ClusterServiceVersion) ([]map[string]interface{}, error) {
+	almExamples := ""
+	if csv != nil {
+		// map of string interface which consist of ALM examples from the CSVList
+		almExamples = csv.ObjectMeta.Annotations["alm-examples"]
+	}
+	var almList []map[string]interface{}
+
+	if err := yaml.Unmarshal([]byte(almExamples), &almList); err != nil {
+		return nil, err
+	}
+
+	return almList, nil
+}
+
 // OperandInstall installs the operand from the ALMExamples in the ca.namespace
 func operandInstall(ctx context.Context, opts ...auditOption) (auditFn, auditCleanupFn) {
 	var options auditOptions
@@ -75,14 +83,33 @@ func operandInstall(ctx context.Context, opts ...auditOption) (auditFn, auditCle
 			return fmt.Errorf("exiting OperandInstall since CSV install has failed")
 		}
 
-		for _, cr := range options.customResources {
+		// warnings sent before the operands are created belong to other requests
+		drainAPIWarnings(options)
+
+		schemaViolations := map[int][]string{}
+		operandWarnings := map[int][]string{}
+		for i, cr := range options.customResources {
 			obj := &unstructured.Unstructured{Object: cr}
 
 			// set the namespace of CR to the namespace of the subscription
 			obj.SetNamespace(options.namespace)
 
+			// validate the CR offline first so that malformed examples are reported with field paths
+			violations, err := validateAgainstCRD(ctx, options.client, obj)
+			if err != nil {
+				logger.Errorw("could not validate resource against its CRD", "error", err, "namespace", options.namespace)
+			}
+			if len(violations) > 0 {
+				logger.Infow("resource does not match its CRD schema", "kind", obj.GetKind(), "name", obj.GetName(), "violations", violations)
+				schemaViolations[i] = violations
+			}
+
 			// create the resource using the dynamic client and log the error if it occurs
-			err := options.client.CreateUnstructured(ctx, obj)
+			err = options.client.CreateUnstructured(ctx, obj)
+			if warnings := drainAPIWarnings(options); len(warnings) > 0 {
+				logger.Infow("API server sent warnings creating resource", "kind", obj.GetKind(), "name", obj.GetName(), "warnings", warnings)
+				operandWarnings[i] = warnings
+			}
 			if err != nil {
 				// If there is an error, log and continue
 				logger.Errorw("could not create resource", "error", err, "namespace", options.namespace)
@@ -98,22 +125,26 @@ func operandInstall(ctx context.Context, opts ...auditOption) (auditFn, auditCle
 		defer file.Close()
 
 		err = report.OperandInstallJsonReport(file, report.TemplateData{
-			CustomResources: options.customResources,
-			OcpVersion:      options.ocpVersion,
-			Subscription:    *options.subscription,
-			Csv:             options.csv,
-			OperandCount:    len(options.operands),
+			CustomResources:  options.customResources,
+			OcpVersion:       options.ocpVersion,
+			Subscription:     *options.subscription,
+			Csv:              options.csv,
+			OperandCount:     len(options.operands),
+			SchemaViolations: schemaViolations,
+			OperandWarnings:  operandWarnings,
 		})
 		if err != nil {
 			return fmt.Errorf("could not generate operand install JSON report: %v", err)
 		}
 
 		err = report.OperandInstallTextReport(options.reportWriter, report.TemplateData{
-			CustomResources: options.customResources,
-			OcpVersion:      options.ocpVersion,
-			Subscription:    *options.subscription,
-			Csv:             options.csv,
-			OperandCount:    len(options.operands),
+			CustomResources:  options.customResources,
+			OcpVersion:       options.ocpVersion,
+			Subscription:     *options.subscription,
+			Csv:              options.csv,
+			OperandCount:     len(options.operands),
+			SchemaViolations: schemaViolations,
+			OperandWarnings:  operandWarnings,
 		})
 		if err != nil {
 			return fmt.Errorf("could not generate operand install text report: %v", err)
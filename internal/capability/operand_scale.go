@@ -0,0 +1,229 @@
+package capability
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/opdev/opcap/internal/logger"
+	"github.com/opdev/opcap/internal/report"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+)
+
+// replicaFieldPaths are the replica-like fields looked for, in order, when the user did not provide one
+var replicaFieldPaths = []string{"spec.replicas", "spec.size", "spec.nodes"}
+
+// operandScale scales every operand up by one and back down through its replica-like field and checks
+// that the owned workloads converge to the new size and that the operand status reports it
+func operandScale(ctx context.Context, opts ...auditOption) (auditFn, auditCleanupFn) {
+	var options auditOptions
+	for _, opt := range opts {
+		err := opt(&options)
+		if err != nil {
+			return func(_ context.Context) error {
+					return fmt.Errorf("option failed: %v", err)
+				}, func(_ context.Context) error {
+					return nil
+				}
+		}
+	}
+
+	if err := extractAlmExamples(ctx, &options); err != nil {
+		logger.Errorf("could not get ALM Examples: %v", err)
+	}
+
+	return func(ctx context.Context) error {
+		logger.Debugw("scaling operand for operator", "package", options.subscription.Package, "channel", options.subscription.Channel, "installmode", options.subscription.InstallModeType)
+
+		if len(options.customResources) == 0 {
+			logger.Infow("exiting OperandScale since no ALM_Examples found in CSV")
+			return nil
+		}
+
+		results := []report.OperandScaleResult{}
+		for _, cr := range options.customResources {
+			result, err := scaleOperand(ctx, options, &unstructured.Unstructured{Object: cr})
+			if err != nil {
+				logger.Errorw("could not scale operand", "error", err, "namespace", options.namespace)
+				continue
+			}
+			results = append(results, result)
+		}
+
+		file, err := options.fs.OpenFile("operand_scale_report.json", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		data := report.TemplateData{
+			OcpVersion:          options.ocpVersion,
+			Subscription:        *options.subscription,
+			OperandScaleResults: results,
+		}
+
+		if err := report.OperandScaleJsonReport(file, data); err != nil {
+			return fmt.Errorf("could not generate operand scale JSON report: %v", err)
+		}
+
+		if err := report.OperandScaleTextReport(options.reportWriter, data); err != nil {
+			return fmt.Errorf("could not generate operand scale text report: %v", err)
+		}
+
+		return nil
+	}, func(_ context.Context) error { return nil }
+}
+
+func scaleOperand(ctx context.Context, options auditOptions, cr *unstructured.Unstructured) (report.OperandScaleResult, error) {
+	result := report.OperandScaleResult{
+		Kind: cr.GetKind(),
+		Name: cr.GetName(),
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(cr.GroupVersionKind())
+	if err := options.client.GetUnstructured(ctx, options.namespace, result.Name, obj); err != nil {
+		return result, fmt.Errorf("could not get operand: %v", err)
+	}
+
+	path, replicas, found := replicaField(obj, options.scaleFieldPath)
+	if !found {
+		result.Message = "no replica-like field found"
+		return result, nil
+	}
+	result.Field = strings.Join(path, ".")
+
+	result.ScaleUp = scaleOperandTo(ctx, options, obj, path, replicas+1)
+	result.ScaleDown = scaleOperandTo(ctx, options, obj, path, replicas)
+
+	return result, nil
+}
+
+// scaleOperandTo sets the replica-like field to replicas and waits for the operand to converge
+func scaleOperandTo(ctx context.Context, options auditOptions, obj *unstructured.Unstructured, path []string, replicas int64) report.ScaleStep {
+	step := report.ScaleStep{Replicas: replicas}
+
+	start := time.Now()
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := options.client.GetUnstructured(ctx, obj.GetNamespace(), obj.GetName(), obj); err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedField(obj.Object, replicas, path...); err != nil {
+			return err
+		}
+		return options.client.UpdateUnstructured(ctx, obj)
+	})
+	if err != nil {
+		step.Message = fmt.Sprintf("could not update operand: %v", err)
+		return step
+	}
+
+	err = wait.PollImmediateWithContext(ctx, time.Second, options.csvWaitTime, func(ctx context.Context) (bool, error) {
+		if err := options.client.GetUnstructured(ctx, obj.GetNamespace(), obj.GetName(), obj); err != nil {
+			return false, err
+		}
+		workloads, err := ownedResources(ctx, options.client, obj.GetNamespace(), obj.GetUID())
+		if err != nil {
+			return false, err
+		}
+		step.Workloads, step.WorkloadsConverged = workloadsConverged(workloads, replicas)
+		step.StatusReported = statusReportsReplicas(obj, path, replicas)
+		return step.WorkloadsConverged && step.StatusReported, nil
+	})
+	if err != nil && err != wait.ErrWaitTimeout {
+		step.Message = fmt.Sprintf("could not wait for operand to scale: %v", err)
+	}
+	step.Duration = time.Since(start).Round(time.Second)
+
+	return step
+}
+
+// replicaField finds the replica-like field of an operand. A path provided by the user takes precedence
+// over the well-known ones.
+func replicaField(obj *unstructured.Unstructured, userPath string) ([]string, int64, bool) {
+	paths := replicaFieldPaths
+	if userPath != "" {
+		paths = append([]string{userPath}, paths...)
+	}
+
+	for _, p := range paths {
+		path := strings.Split(p, ".")
+		if replicas, ok := nestedInt64(obj.Object, path...); ok {
+			return path, replicas, true
+		}
+	}
+
+	return nil, 0, false
+}
+
+// nestedInt64 reads a whole number whether it was decoded from JSON as an int64 or a float64
+func nestedInt64(obj map[string]interface{}, fields ...string) (int64, bool) {
+	val, found, err := unstructured.NestedFieldNoCopy(obj, fields...)
+	if !found || err != nil {
+		return 0, false
+	}
+
+	switch v := val.(type) {
+	case int64:
+		return v, true
+	case int:
+		return int64(v), true
+	case float64:
+		if v == float64(int64(v)) {
+			return int64(v), true
+		}
+	}
+
+	return 0, false
+}
+
+// workloadsConverged counts the owned Deployments and StatefulSets and tells whether there is at least one and
+// every one of them was scaled to replicas, has observed its latest generation and has all of its replicas ready
+func workloadsConverged(workloads []unstructured.Unstructured, replicas int64) (int, bool) {
+	count := 0
+	converged := true
+	for _, w := range workloads {
+		if w.GetKind() != "Deployment" && w.GetKind() != "StatefulSet" {
+			continue
+		}
+		count++
+		desired, ok := nestedInt64(w.Object, "spec", "replicas")
+		if !ok {
+			desired = 1
+		}
+		ready, _ := nestedInt64(w.Object, "status", "readyReplicas")
+		observed, _ := nestedInt64(w.Object, "status", "observedGeneration")
+		if desired != replicas || ready != desired || observed < w.GetGeneration() {
+			converged = false
+		}
+	}
+
+	return count, count > 0 && converged
+}
+
+// statusReportsReplicas checks the status counterpart of the replica-like field, as well as the usual
+// replica counters, for the expected size
+func statusReportsReplicas(obj *unstructured.Unstructured, path []string, replicas int64) bool {
+	candidates := [][]string{
+		{"status", "replicas"},
+		{"status", "readyReplicas"},
+		{"status", "size"},
+		{"status", "nodes"},
+	}
+	if len(path) > 1 && path[0] == "spec" {
+		candidates = append([][]string{append([]string{"status"}, path[1:]...)}, candidates...)
+	}
+
+	for _, c := range candidates {
+		if v, ok := nestedInt64(obj.Object, c...); ok && v == replicas {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,185 @@
+package capability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opdev/opcap/internal/operator"
+	operatorv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/spf13/afero"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("OperandScale audit", func() {
+	Context("finding the replica-like field", func() {
+		var obj *unstructured.Unstructured
+
+		BeforeEach(func() {
+			obj = &unstructured.Unstructured{Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"size":    float64(3),
+					"cluster": map[string]interface{}{"members": int64(5)},
+				},
+			}}
+		})
+
+		When("no path is provided", func() {
+			It("should use the well-known fields", func() {
+				path, replicas, found := replicaField(obj, "")
+				Expect(found).To(BeTrue())
+				Expect(path).To(Equal([]string{"spec", "size"}))
+				Expect(replicas).To(Equal(int64(3)))
+			})
+		})
+		When("a path is provided", func() {
+			It("should take precedence", func() {
+				path, replicas, found := replicaField(obj, "spec.cluster.members")
+				Expect(found).To(BeTrue())
+				Expect(path).To(Equal([]string{"spec", "cluster", "members"}))
+				Expect(replicas).To(Equal(int64(5)))
+			})
+		})
+		When("there is no such field", func() {
+			It("should not find anything", func() {
+				_, _, found := replicaField(&unstructured.Unstructured{Object: map[string]interface{}{}}, "")
+				Expect(found).To(BeFalse())
+			})
+		})
+	})
+
+	Context("checking workloads", func() {
+		newDeployment := func(replicas, ready int64) unstructured.Unstructured {
+			return unstructured.Unstructured{Object: map[string]interface{}{
+				"kind":     "Deployment",
+				"metadata": map[string]interface{}{"generation": int64(2)},
+				"spec":     map[string]interface{}{"replicas": replicas},
+				"status":   map[string]interface{}{"readyReplicas": ready, "observedGeneration": int64(2)},
+			}}
+		}
+
+		It("should converge when every replica is ready", func() {
+			count, converged := workloadsConverged([]unstructured.Unstructured{newDeployment(2, 2)}, 2)
+			Expect(count).To(Equal(1))
+			Expect(converged).To(BeTrue())
+		})
+		It("should not converge while replicas are missing", func() {
+			_, converged := workloadsConverged([]unstructured.Unstructured{newDeployment(3, 3), newDeployment(3, 1)}, 3)
+			Expect(converged).To(BeFalse())
+		})
+		It("should not converge when the workloads were not scaled to the requested size", func() {
+			_, converged := workloadsConverged([]unstructured.Unstructured{newDeployment(2, 2)}, 3)
+			Expect(converged).To(BeFalse())
+		})
+		It("should not converge without workloads", func() {
+			count, converged := workloadsConverged(nil, 2)
+			Expect(count).To(BeZero())
+			Expect(converged).To(BeFalse())
+		})
+	})
+
+	Context("checking the operand status", func() {
+		It("should look at the status counterpart of the field", func() {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{
+				"status": map[string]interface{}{"cluster": map[string]interface{}{"members": int64(4)}},
+			}}
+			Expect(statusReportsReplicas(obj, []string{"spec", "cluster", "members"}, 4)).To(BeTrue())
+			Expect(statusReportsReplicas(obj, []string{"spec", "cluster", "members"}, 3)).To(BeFalse())
+		})
+	})
+
+	When("the operator never scales its workloads down", func() {
+		It("should report the operand as not converged", func() {
+			csv := &operatorv1alpha1.ClusterServiceVersion{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "testpackage.v1.0.0",
+					Namespace: "testns",
+					Annotations: map[string]string{
+						"alm-examples": `[{"apiVersion":"example.com/v1","kind":"Example","metadata":{"name":"example"},"spec":{"replicas":1}}]`,
+					},
+				},
+			}
+			operand := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "example.com/v1",
+				"kind":       "Example",
+				"metadata":   map[string]interface{}{"name": "example", "namespace": "testns", "uid": "example-uid"},
+				"spec":       map[string]interface{}{"replicas": int64(1)},
+				"status":     map[string]interface{}{"replicas": int64(2)},
+			}}
+			// the operator scaled its deployment up but never back down
+			deployment := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata": map[string]interface{}{
+					"name":            "example",
+					"namespace":       "testns",
+					"ownerReferences": []interface{}{map[string]interface{}{"apiVersion": "example.com/v1", "kind": "Example", "name": "example", "uid": "example-uid"}},
+				},
+				"spec":   map[string]interface{}{"replicas": int64(2)},
+				"status": map[string]interface{}{"readyReplicas": int64(2)},
+			}}
+			fs := afero.NewMemMapFs()
+			output := &bytes.Buffer{}
+
+			auditFn, _ := operandScale(context.TODO(),
+				withClient(operator.NewFakeOpClient(csv, operand, deployment)),
+				withNamespace("testns"),
+				withSubscription(&operator.SubscriptionData{Package: "testpackage"}),
+				withTimeout(time.Second),
+				withFilesystem(fs),
+				withReportWriter(output),
+			)
+			Expect(auditFn(context.TODO())).To(Succeed())
+
+			Expect(output.String()).To(ContainSubstring("Replica Field: spec.replicas"))
+			Expect(output.String()).To(ContainSubstring("Scale Up To: 2\n  Workloads Converged: true\n  Status Reported: true"))
+			Expect(output.String()).To(ContainSubstring("Scale Down To: 1\n  Workloads Converged: false\n  Status Reported: false"))
+
+			report, err := afero.ReadFile(fs, "operand_scale_report.json")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(json.Valid(report)).To(BeTrue())
+			Expect(string(report)).To(ContainSubstring(`"message":"not converged"`))
+		})
+	})
+
+	When("the operand owns no workloads", func() {
+		It("should report no workloads rather than converged", func() {
+			csv := &operatorv1alpha1.ClusterServiceVersion{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "testpackage.v1.0.0",
+					Namespace: "testns",
+					Annotations: map[string]string{
+						"alm-examples": `[{"apiVersion":"example.com/v1","kind":"Example","metadata":{"name":"example"},"spec":{"replicas":1}}]`,
+					},
+				},
+			}
+			operand := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "example.com/v1",
+				"kind":       "Example",
+				"metadata":   map[string]interface{}{"name": "example", "namespace": "testns"},
+				"spec":       map[string]interface{}{"replicas": int64(1)},
+			}}
+			fs := afero.NewMemMapFs()
+			output := &bytes.Buffer{}
+
+			auditFn, _ := operandScale(context.TODO(),
+				withClient(operator.NewFakeOpClient(csv, operand)),
+				withNamespace("testns"),
+				withSubscription(&operator.SubscriptionData{Package: "testpackage"}),
+				withTimeout(time.Second),
+				withFilesystem(fs),
+				withReportWriter(output),
+			)
+			Expect(auditFn(context.TODO())).To(Succeed())
+
+			Expect(output.String()).To(ContainSubstring("Scale Up To: 2\n  Workloads Converged: no workloads"))
+			report, err := afero.ReadFile(fs, "operand_scale_report.json")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(report)).To(ContainSubstring(`"message":"no workloads"`))
+		})
+	})
+})
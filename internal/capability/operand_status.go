@@ -0,0 +1,227 @@
+package capability
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/opdev/opcap/internal/logger"
+	"github.com/opdev/opcap/internal/report"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// conditionReason is the format of the reason of a metav1.Condition, its type being a qualified name
+var conditionReason = regexp.MustCompile(`^[A-Za-z]([A-Za-z0-9_,:]*[A-Za-z0-9_])?$`)
+
+// operandStatus checks that every operand created from the ALM examples exposes a status following the
+// Kubernetes conditions convention and that the operator keeps it up to date with the operand generation
+func operandStatus(ctx context.Context, opts ...auditOption) (auditFn, auditCleanupFn) {
+	var options auditOptions
+	for _, opt := range opts {
+		err := opt(&options)
+		if err != nil {
+			return func(_ context.Context) error {
+					return fmt.Errorf("option failed: %v", err)
+				}, func(_ context.Context) error {
+					return nil
+				}
+		}
+	}
+
+	if err := extractAlmExamples(ctx, &options); err != nil {
+		logger.Errorf("could not get ALM Examples: %v", err)
+	}
+
+	return func(ctx context.Context) error {
+		logger.Debugw("checking operand status for operator", "package", options.subscription.Package, "channel", options.subscription.Channel, "installmode", options.subscription.InstallModeType)
+
+		if len(options.customResources) == 0 {
+			logger.Infow("exiting OperandStatus since no ALM_Examples found in CSV")
+			return nil
+		}
+
+		results := []report.OperandStatusResult{}
+		for _, cr := range options.customResources {
+			result, err := checkOperandStatus(ctx, options, &unstructured.Unstructured{Object: cr})
+			if err != nil {
+				logger.Errorw("could not check operand status", "error", err, "namespace", options.namespace)
+				continue
+			}
+			results = append(results, result)
+		}
+
+		file, err := options.fs.OpenFile("operand_status_report.json", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		data := report.TemplateData{
+			OcpVersion:           options.ocpVersion,
+			Subscription:         *options.subscription,
+			OperandStatusResults: results,
+		}
+
+		if err := report.OperandStatusJsonReport(file, data); err != nil {
+			return fmt.Errorf("could not generate operand status JSON report: %v", err)
+		}
+
+		if err := report.OperandStatusTextReport(options.reportWriter, data); err != nil {
+			return fmt.Errorf("could not generate operand status text report: %v", err)
+		}
+
+		return nil
+	}, func(_ context.Context) error { return nil }
+}
+
+// checkOperandStatus waits, up to the audit timeout, for the operator to report a status for the current
+// generation of the operand and then validates it
+func checkOperandStatus(ctx context.Context, options auditOptions, cr *unstructured.Unstructured) (report.OperandStatusResult, error) {
+	result := report.OperandStatusResult{
+		Kind: cr.GetKind(),
+		Name: cr.GetName(),
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(cr.GroupVersionKind())
+	err := wait.PollImmediateWithContext(ctx, time.Second, options.csvWaitTime, func(ctx context.Context) (bool, error) {
+		if err := options.client.GetUnstructured(ctx, options.namespace, result.Name, obj); err != nil {
+			return false, err
+		}
+		generation, ok := observedGeneration(obj)
+		return ok && generation >= obj.GetGeneration(), nil
+	})
+	if apierrors.IsNotFound(err) {
+		result.Issues = append(result.Issues, "operand was not found")
+		return result, nil
+	}
+	if err != nil && err != wait.ErrWaitTimeout {
+		return result, fmt.Errorf("could not get operand: %v", err)
+	}
+
+	return validateStatus(obj), nil
+}
+
+// observedGeneration returns the generation the status was computed for, from status.observedGeneration
+// or, failing that, the lowest observedGeneration among the conditions
+func observedGeneration(obj *unstructured.Unstructured) (int64, bool) {
+	if generation, ok, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration"); ok {
+		return generation, true
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	var lowest int64
+	found := false
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		generation, ok, _ := unstructured.NestedInt64(condition, "observedGeneration")
+		if !ok {
+			continue
+		}
+		if !found || generation < lowest {
+			lowest = generation
+		}
+		found = true
+	}
+
+	return lowest, found
+}
+
+// validateStatus checks the status of an operand against the Kubernetes conditions convention
+func validateStatus(obj *unstructured.Unstructured) report.OperandStatusResult {
+	result := report.OperandStatusResult{
+		Kind:       obj.GetKind(),
+		Name:       obj.GetName(),
+		Generation: obj.GetGeneration(),
+	}
+
+	status, found, _ := unstructured.NestedMap(obj.Object, "status")
+	if !found || len(status) == 0 {
+		result.Issues = append(result.Issues, "operand has no status")
+		return result
+	}
+	result.HasStatus = true
+
+	generation, ok := observedGeneration(obj)
+	if !ok {
+		result.Issues = append(result.Issues, "status has no observedGeneration")
+	} else {
+		result.ObservedGeneration = generation
+		result.UpToDate = generation >= obj.GetGeneration()
+		if !result.UpToDate {
+			result.Issues = append(result.Issues, fmt.Sprintf("status was not updated for generation %d", obj.GetGeneration()))
+		}
+	}
+
+	conditions, found, err := unstructured.NestedSlice(status, "conditions")
+	if err != nil {
+		result.Issues = append(result.Issues, "status.conditions is not a list")
+		return result
+	}
+	if !found || len(conditions) == 0 {
+		result.Issues = append(result.Issues, "status has no conditions")
+		return result
+	}
+
+	for i, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			result.Issues = append(result.Issues, fmt.Sprintf("condition %d is not an object", i))
+			continue
+		}
+		result.Conditions++
+		result.Issues = append(result.Issues, validateCondition(i, condition)...)
+	}
+
+	return result
+}
+
+func validateCondition(index int, condition map[string]interface{}) []string {
+	issues := []string{}
+
+	conditionType, _, _ := unstructured.NestedString(condition, "type")
+	name := fmt.Sprintf("condition %d", index)
+	if conditionType != "" {
+		name = fmt.Sprintf("condition %s", conditionType)
+	}
+
+	if len(validation.IsQualifiedName(conditionType)) > 0 {
+		issues = append(issues, fmt.Sprintf("%s has an invalid type %q", name, conditionType))
+	}
+
+	switch status, _, _ := unstructured.NestedString(condition, "status"); status {
+	case "True", "False", "Unknown":
+	default:
+		issues = append(issues, fmt.Sprintf("%s has an invalid status %q", name, status))
+	}
+
+	if reason, _, _ := unstructured.NestedString(condition, "reason"); !conditionReason.MatchString(reason) {
+		issues = append(issues, fmt.Sprintf("%s has an invalid reason %q", name, reason))
+	}
+
+	if _, ok, _ := unstructured.NestedString(condition, "message"); !ok {
+		issues = append(issues, fmt.Sprintf("%s has no message", name))
+	}
+
+	lastTransitionTime, ok, _ := unstructured.NestedString(condition, "lastTransitionTime")
+	if !ok {
+		issues = append(issues, fmt.Sprintf("%s has no lastTransitionTime", name))
+	} else if _, err := time.Parse(time.RFC3339, lastTransitionTime); err != nil {
+		issues = append(issues, fmt.Sprintf("%s has an invalid lastTransitionTime %q", name, lastTransitionTime))
+	}
+
+	if _, ok, _ := unstructured.NestedInt64(condition, "observedGeneration"); !ok {
+		issues = append(issues, fmt.Sprintf("%s has no observedGeneration", name))
+	}
+
+	return issues
+}
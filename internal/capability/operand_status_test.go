@@ -0,0 +1,140 @@
+package capability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opdev/opcap/internal/operator"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("OperandStatus audit", func() {
+	newOperand := func(name string, status map[string]interface{}) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		if status != nil {
+			obj.Object["status"] = status
+		}
+		obj.SetAPIVersion("example.com/v1")
+		obj.SetKind("Example")
+		obj.SetName(name)
+		obj.SetNamespace("testns")
+		obj.SetGeneration(2)
+		return obj
+	}
+
+	validCondition := map[string]interface{}{
+		"type":               "Ready",
+		"status":             "True",
+		"reason":             "Reconciled",
+		"message":            "all good",
+		"lastTransitionTime": "2022-08-01T10:00:00Z",
+		"observedGeneration": int64(2),
+	}
+
+	Context("validating a status", func() {
+		It("should accept a standard status", func() {
+			result := validateStatus(newOperand("example", map[string]interface{}{
+				"conditions": []interface{}{validCondition},
+			}))
+			Expect(result.HasStatus).To(BeTrue())
+			Expect(result.UpToDate).To(BeTrue())
+			Expect(result.Conditions).To(Equal(1))
+			Expect(result.Issues).To(BeEmpty())
+		})
+		It("should report a missing status", func() {
+			result := validateStatus(newOperand("example", nil))
+			Expect(result.HasStatus).To(BeFalse())
+			Expect(result.Issues).To(ConsistOf("operand has no status"))
+		})
+		It("should report non-standard conditions", func() {
+			result := validateStatus(newOperand("example", map[string]interface{}{
+				"observedGeneration": int64(2),
+				"conditions": []interface{}{map[string]interface{}{
+					"type":               "Ready",
+					"status":             "yes",
+					"lastTransitionTime": "yesterday",
+				}},
+			}))
+			Expect(result.UpToDate).To(BeTrue())
+			Expect(result.Issues).To(ConsistOf(
+				`condition Ready has an invalid status "yes"`,
+				`condition Ready has an invalid reason ""`,
+				"condition Ready has no message",
+				`condition Ready has an invalid lastTransitionTime "yesterday"`,
+				"condition Ready has no observedGeneration",
+			))
+		})
+		It("should accept a type prefixed with a domain", func() {
+			condition := map[string]interface{}{}
+			for key, value := range validCondition {
+				condition[key] = value
+			}
+			condition["type"] = "example.com/Ready"
+			result := validateStatus(newOperand("example", map[string]interface{}{
+				"conditions": []interface{}{condition},
+			}))
+			Expect(result.Issues).To(BeEmpty())
+		})
+		It("should report invalid types and reasons", func() {
+			condition := map[string]interface{}{}
+			for key, value := range validCondition {
+				condition[key] = value
+			}
+			condition["type"] = "example.com/Ready/Now"
+			condition["reason"] = "Reconciled/Done"
+			result := validateStatus(newOperand("example", map[string]interface{}{
+				"conditions": []interface{}{condition},
+			}))
+			Expect(result.Issues).To(ConsistOf(
+				`condition example.com/Ready/Now has an invalid type "example.com/Ready/Now"`,
+				`condition example.com/Ready/Now has an invalid reason "Reconciled/Done"`,
+			))
+		})
+		It("should report a stale status", func() {
+			result := validateStatus(newOperand("example", map[string]interface{}{
+				"observedGeneration": int64(1),
+				"conditions":         []interface{}{validCondition},
+			}))
+			Expect(result.UpToDate).To(BeFalse())
+			Expect(result.Issues).To(ConsistOf("status was not updated for generation 2"))
+		})
+	})
+
+	Context("running the audit", func() {
+		It("should report every operand", func() {
+			fs := afero.NewMemMapFs()
+			output := &bytes.Buffer{}
+			auditFn, _ := operandStatus(context.TODO(),
+				withClient(operator.NewFakeOpClient(
+					newOperand("valid", map[string]interface{}{"conditions": []interface{}{validCondition}}),
+					newOperand("nostatus", nil),
+				)),
+				withNamespace("testns"),
+				withSubscription(&operator.SubscriptionData{Package: "testpackage"}),
+				withCustomResources([]map[string]interface{}{
+					newOperand("valid", nil).Object,
+					newOperand("nostatus", nil).Object,
+				}),
+				withTimeout(time.Second),
+				withFilesystem(fs),
+				withReportWriter(output),
+			)
+			Expect(auditFn(context.TODO())).To(Succeed())
+
+			Expect(output.String()).To(ContainSubstring("Operand Name: valid\nStatus: Present\nConditions: 1"))
+			Expect(output.String()).To(ContainSubstring("Operand Name: nostatus\nStatus: Missing"))
+			Expect(output.String()).To(ContainSubstring("Issue: operand has no status"))
+
+			report, err := afero.ReadFile(fs, "operand_status_report.json")
+			Expect(err).ToNot(HaveOccurred())
+			for _, line := range bytes.Split(bytes.TrimSpace(report), []byte("\n")) {
+				Expect(json.Valid(line)).To(BeTrue())
+			}
+		})
+	})
+})
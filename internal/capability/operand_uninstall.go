@@ -0,0 +1,150 @@
+package capability
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/opdev/opcap/internal/logger"
+	"github.com/opdev/opcap/internal/report"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// operandUninstall deletes every operand created from the ALM examples and checks that the operator finishes
+// its finalizer work and that the owned secondary resources get garbage-collected. It does not force anything:
+// finalizers are only stripped later by the OperandInstall cleanup.
+func operandUninstall(ctx context.Context, opts ...auditOption) (auditFn, auditCleanupFn) {
+	var options auditOptions
+	for _, opt := range opts {
+		err := opt(&options)
+		if err != nil {
+			return func(_ context.Context) error {
+					return fmt.Errorf("option failed: %v", err)
+				}, func(_ context.Context) error {
+					return nil
+				}
+		}
+	}
+
+	if err := extractAlmExamples(ctx, &options); err != nil {
+		logger.Errorf("could not get ALM Examples: %v", err)
+	}
+
+	return func(ctx context.Context) error {
+		logger.Debugw("uninstalling operand for operator", "package", options.subscription.Package, "channel", options.subscription.Channel, "installmode", options.subscription.InstallModeType)
+
+		if len(options.customResources) == 0 {
+			logger.Infow("exiting OperandUninstall since no ALM_Examples found in CSV")
+			return nil
+		}
+
+		results := []report.OperandUninstallResult{}
+		for _, cr := range options.customResources {
+			result, err := uninstallOperand(ctx, options, &unstructured.Unstructured{Object: cr})
+			if err != nil {
+				logger.Errorw("could not uninstall operand", "error", err, "namespace", options.namespace)
+				continue
+			}
+			results = append(results, result)
+		}
+
+		file, err := options.fs.OpenFile("operand_uninstall_report.json", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		data := report.TemplateData{
+			OcpVersion:              options.ocpVersion,
+			Subscription:            *options.subscription,
+			OperandUninstallResults: results,
+		}
+
+		if err := report.OperandUninstallJsonReport(file, data); err != nil {
+			return fmt.Errorf("could not generate operand uninstall JSON report: %v", err)
+		}
+
+		if err := report.OperandUninstallTextReport(options.reportWriter, data); err != nil {
+			return fmt.Errorf("could not generate operand uninstall text report: %v", err)
+		}
+
+		return nil
+	}, func(_ context.Context) error { return nil }
+}
+
+// uninstallOperand deletes a single operand and waits, up to the audit timeout, first for the operand
+// to be gone and then for its owned resources to be garbage-collected
+func uninstallOperand(ctx context.Context, options auditOptions, cr *unstructured.Unstructured) (report.OperandUninstallResult, error) {
+	result := report.OperandUninstallResult{
+		Kind: cr.GetKind(),
+		Name: cr.GetName(),
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(cr.GroupVersionKind())
+	if err := options.client.GetUnstructured(ctx, options.namespace, result.Name, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			result.NotFound = true
+			result.Message = "operand was not found, nothing to uninstall"
+			return result, nil
+		}
+		return result, fmt.Errorf("could not get operand: %v", err)
+	}
+
+	owned, err := ownedResources(ctx, options.client, options.namespace, obj.GetUID())
+	if err != nil {
+		return result, err
+	}
+
+	start := time.Now()
+	if err := options.client.DeleteUnstructured(ctx, obj); err != nil {
+		return result, fmt.Errorf("could not delete operand: %v", err)
+	}
+
+	err = wait.PollImmediateWithContext(ctx, time.Second, options.csvWaitTime, func(ctx context.Context) (bool, error) {
+		err := options.client.GetUnstructured(ctx, options.namespace, result.Name, obj)
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+	if err != nil && err != wait.ErrWaitTimeout {
+		return result, fmt.Errorf("could not wait for operand deletion: %v", err)
+	}
+	if err == wait.ErrWaitTimeout {
+		result.StuckFinalizers = obj.GetFinalizers()
+		result.Message = "operand was not deleted before timeout"
+		return result, nil
+	}
+	result.Deleted = true
+	result.DeletionTime = time.Since(start).Round(time.Second)
+
+	// Whatever is still around once the garbage collector had its chance is an orphan
+	err = wait.PollImmediateWithContext(ctx, time.Second, options.csvWaitTime, func(ctx context.Context) (bool, error) {
+		remaining := []string{}
+		for _, o := range owned {
+			dependent := &unstructured.Unstructured{}
+			dependent.SetGroupVersionKind(o.GroupVersionKind())
+			err := options.client.GetUnstructured(ctx, o.GetNamespace(), o.GetName(), dependent)
+			if apierrors.IsNotFound(err) || (err == nil && dependent.GetUID() != o.GetUID()) {
+				continue
+			}
+			remaining = append(remaining, resourceName(o))
+		}
+		result.OrphanedResources = remaining
+		return len(remaining) == 0, nil
+	})
+	switch {
+	case err == wait.ErrWaitTimeout:
+		result.GCTimedOut = true
+		result.Message = "owned resources were not garbage-collected before timeout"
+	case err != nil:
+		result.Message = fmt.Sprintf("could not wait for owned resources to be garbage-collected: %v", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,124 @@
+package capability
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opdev/opcap/internal/operator"
+	operatorv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/spf13/afero"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("OperandUninstall audit", func() {
+	var (
+		csv     *operatorv1alpha1.ClusterServiceVersion
+		operand *unstructured.Unstructured
+		fs      afero.Fs
+		output  *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		csv = &operatorv1alpha1.ClusterServiceVersion{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "testpackage.v1.0.0",
+				Namespace: "testns",
+				Annotations: map[string]string{
+					"alm-examples": `[{"apiVersion":"example.com/v1","kind":"Example","metadata":{"name":"example"},"spec":{}}]`,
+				},
+			},
+		}
+		operand = &unstructured.Unstructured{}
+		operand.SetAPIVersion("example.com/v1")
+		operand.SetKind("Example")
+		operand.SetName("example")
+		operand.SetNamespace("testns")
+		operand.SetUID("operand-uid")
+		fs = afero.NewMemMapFs()
+		output = &bytes.Buffer{}
+	})
+
+	runAudit := func(objs ...runtime.Object) {
+		client := operator.NewFakeOpClient(objs...)
+		auditFn, cleanupFn := operandUninstall(context.TODO(),
+			withClient(client),
+			withNamespace("testns"),
+			withSubscription(&operator.SubscriptionData{Package: "testpackage"}),
+			withTimeout(2*time.Second),
+			withFilesystem(fs),
+			withReportWriter(output),
+		)
+		Expect(auditFn(context.TODO())).To(Succeed())
+		Expect(cleanupFn(context.TODO())).To(Succeed())
+	}
+
+	When("the operand is deleted and has nothing left behind", func() {
+		It("should report it as deleted", func() {
+			runAudit(csv, operand)
+			Expect(output.String()).To(ContainSubstring("Operand Deletion: Succeeded"))
+			report, err := afero.ReadFile(fs, "operand_uninstall_report.json")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(report)).To(MatchJSON(`{"package":"testpackage","Operand Kind":"Example","Operand Name":"example","message":"deleted","deletionTime":"0s","gcTimedOut":false,"stuckFinalizers":[],"orphanedResources":[]}`))
+		})
+	})
+
+	When("the operand finalizer is never removed", func() {
+		BeforeEach(func() {
+			operand.SetFinalizers([]string{"example.com/finalizer"})
+		})
+		It("should report the stuck finalizer", func() {
+			runAudit(csv, operand)
+			Expect(output.String()).To(ContainSubstring("Operand Deletion: Failed"))
+			Expect(output.String()).To(ContainSubstring("Stuck Finalizers: example.com/finalizer"))
+		})
+	})
+
+	When("an owned resource is not garbage-collected", func() {
+		It("should report the orphaned resource", func() {
+			configMap := &corev1.ConfigMap{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "example-config",
+					Namespace: "testns",
+					OwnerReferences: []metav1.OwnerReference{
+						{APIVersion: "example.com/v1", Kind: "Example", Name: "example", UID: "operand-uid"},
+					},
+				},
+			}
+			runAudit(csv, operand, configMap)
+			Expect(output.String()).To(ContainSubstring("Garbage Collection: Timed out"))
+			Expect(output.String()).To(ContainSubstring("Orphaned Resources: ConfigMap/example-config"))
+			report, err := afero.ReadFile(fs, "operand_uninstall_report.json")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(report)).To(ContainSubstring(`"message":"orphaned"`))
+			Expect(string(report)).To(ContainSubstring(`"gcTimedOut":true`))
+		})
+	})
+
+	When("the operand is already gone", func() {
+		It("should report it as skipped rather than stuck", func() {
+			runAudit(csv)
+			Expect(output.String()).To(ContainSubstring("Operand Deletion: Skipped"))
+			Expect(output.String()).To(ContainSubstring("Message: operand was not found, nothing to uninstall"))
+			report, err := afero.ReadFile(fs, "operand_uninstall_report.json")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(report)).To(ContainSubstring(`"message":"not found"`))
+		})
+	})
+
+	When("there are no ALM examples", func() {
+		BeforeEach(func() {
+			csv.Annotations = map[string]string{}
+		})
+		It("should not write a report", func() {
+			runAudit(csv)
+			Expect(output.String()).To(BeEmpty())
+		})
+	})
+})
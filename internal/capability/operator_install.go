@@ -90,21 +90,33 @@ func operatorInstall(ctx context.Context, opts ...auditOption) (auditFn, auditCl
 		}
 		defer file.Close()
 
+		ocpSupport := ocpSupport(options.declaredOcpVersions, options.ocpVersion)
+		ocpSupportMismatch := ocpSupportMismatch(ocpSupport, options.csv, options.csvTimeout)
+		if ocpSupportMismatch != "" {
+			logger.Infow("install outcome does not match declared OpenShift versions", "package", options.subscription.Package, "ocpVersion", options.ocpVersion, "declaredOcpVersions", options.declaredOcpVersions, "mismatch", ocpSupportMismatch)
+		}
+
 		err = report.OperatorInstallJsonReport(file, report.TemplateData{
-			OcpVersion:   options.ocpVersion,
-			Subscription: *options.subscription,
-			Csv:          options.csv,
-			CsvTimeout:   options.csvTimeout,
+			OcpVersion:          options.ocpVersion,
+			Subscription:        *options.subscription,
+			Csv:                 options.csv,
+			CsvTimeout:          options.csvTimeout,
+			DeclaredOcpVersions: options.declaredOcpVersions,
+			OcpSupport:          ocpSupport,
+			OcpSupportMismatch:  ocpSupportMismatch,
 		})
 		if err != nil {
 			return fmt.Errorf("could not generate operator install JSON report: %v", err)
 		}
 
 		err = report.OperatorInstallTextReport(options.reportWriter, report.TemplateData{
-			OcpVersion:   options.ocpVersion,
-			Subscription: *options.subscription,
-			Csv:          options.csv,
-			CsvTimeout:   options.csvTimeout,
+			OcpVersion:          options.ocpVersion,
+			Subscription:        *options.subscription,
+			Csv:                 options.csv,
+			CsvTimeout:          options.csvTimeout,
+			DeclaredOcpVersions: options.declaredOcpVersions,
+			OcpSupport:          ocpSupport,
+			OcpSupportMismatch:  ocpSupportMismatch,
 		})
 		if err != nil {
 			return fmt.Errorf("could not generate operator install text report: %v", err)
@@ -118,3 +130,16 @@ func operatorInstall(ctx context.Context, opts ...auditOption) (auditFn, auditCl
 		return nil
 	}, operatorCleanup(ctx, opts...)
 }
+
+// ocpSupportMismatch flags packages declaring support for the cluster OpenShift version that fail to install and
+// packages not declaring it that install anyway
+func ocpSupportMismatch(ocpSupport string, csv *operatorv1alpha1.ClusterServiceVersion, csvTimeout bool) string {
+	succeeded := !csvTimeout && csv != nil && csv.Status.Phase == operatorv1alpha1.CSVPhaseSucceeded
+	switch {
+	case ocpSupport == report.OcpSupported && !succeeded:
+		return "declares support for this OpenShift version but failed to install"
+	case ocpSupport == report.OcpUnsupported && succeeded:
+		return "does not declare support for this OpenShift version but installed"
+	}
+	return ""
+}
@@ -0,0 +1,247 @@
+package capability
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/opdev/opcap/internal/logger"
+	"github.com/opdev/opcap/internal/report"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// residueKinds are the kinds looked at, cluster-wide, for leftovers after an operator is uninstalled
+var residueKinds = []schema.GroupVersionKind{
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRoleBinding"},
+	{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "ValidatingWebhookConfiguration"},
+	{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "MutatingWebhookConfiguration"},
+	{Group: "", Version: "v1", Kind: "PersistentVolumeClaim"},
+	{Group: "", Version: "v1", Kind: "Service"},
+	{Group: "", Version: "v1", Kind: "Secret"},
+}
+
+// resourceSnapshot records the UIDs of the objects present in the cluster at a point in time
+type resourceSnapshot struct {
+	objects map[types.UID]bool
+	crds    map[string]bool
+}
+
+// operatorResidue snapshots the cluster before the operator is installed. Its cleanup runs after the
+// OperatorInstall cleanup, takes a second snapshot and reports what the operator left behind.
+// It must be placed before OperatorInstall in the audit plan, which WithAuditPlan enforces.
+func operatorResidue(ctx context.Context, opts ...auditOption) (auditFn, auditCleanupFn) {
+	var options auditOptions
+	for _, opt := range opts {
+		err := opt(&options)
+		if err != nil {
+			return func(_ context.Context) error {
+					return fmt.Errorf("option failed: %v", err)
+				}, func(_ context.Context) error {
+					return nil
+				}
+		}
+	}
+
+	var before *resourceSnapshot
+
+	return func(ctx context.Context) error {
+			logger.Debugw("taking pre-install snapshot", "package", options.subscription.Package, "channel", options.subscription.Channel, "installmode", options.subscription.InstallModeType)
+
+			snapshot, err := takeSnapshot(ctx, options)
+			if err != nil {
+				return fmt.Errorf("could not take pre-install snapshot: %v", err)
+			}
+			before = snapshot
+
+			return nil
+		}, func(ctx context.Context) error {
+			if before == nil {
+				return nil
+			}
+			logger.Debugw("looking for operator residue", "package", options.subscription.Package, "channel", options.subscription.Channel, "installmode", options.subscription.InstallModeType)
+
+			// Namespaced leftovers only count once the audit namespaces are really gone
+			if err := waitForNamespacesDeletion(ctx, options, auditNamespaces(options)); err != nil {
+				logger.Errorf("audit namespaces were not deleted: %v", err)
+			}
+
+			leftovers, err := findLeftovers(ctx, options, before)
+			if err != nil {
+				return fmt.Errorf("could not look for operator residue: %v", err)
+			}
+
+			file, err := options.fs.OpenFile("operator_residue_report.json", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+
+			data := report.TemplateData{
+				OcpVersion:   options.ocpVersion,
+				Subscription: *options.subscription,
+				Leftovers:    leftovers,
+			}
+
+			if err := report.OperatorResidueJsonReport(file, data); err != nil {
+				return fmt.Errorf("could not generate operator residue JSON report: %v", err)
+			}
+
+			if err := report.OperatorResidueTextReport(options.reportWriter, data); err != nil {
+				return fmt.Errorf("could not generate operator residue text report: %v", err)
+			}
+
+			return nil
+		}
+}
+
+func takeSnapshot(ctx context.Context, options auditOptions) (*resourceSnapshot, error) {
+	snapshot := &resourceSnapshot{
+		objects: map[types.UID]bool{},
+		crds:    map[string]bool{},
+	}
+
+	for _, gvk := range residueKinds {
+		items, err := listResources(ctx, options.client, gvk, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			snapshot.objects[item.GetUID()] = true
+		}
+	}
+
+	crds := &apiextensionsv1.CustomResourceDefinitionList{}
+	if err := options.client.ListCRDs(ctx, crds); err != nil {
+		return nil, fmt.Errorf("could not list CRDs: %v", err)
+	}
+	for _, crd := range crds.Items {
+		snapshot.crds[crd.Name] = true
+	}
+
+	return snapshot, nil
+}
+
+// findLeftovers lists the objects created since the snapshot that can be attributed to the operator,
+// plus every instance of the CRDs the operator brought along
+func findLeftovers(ctx context.Context, options auditOptions, before *resourceSnapshot) ([]report.Leftover, error) {
+	leftovers := []report.Leftover{}
+
+	crds := &apiextensionsv1.CustomResourceDefinitionList{}
+	if err := options.client.ListCRDs(ctx, crds); err != nil {
+		return nil, fmt.Errorf("could not list CRDs: %v", err)
+	}
+	newCRDs := []apiextensionsv1.CustomResourceDefinition{}
+	newCRDOwners := map[types.UID]string{}
+	for _, crd := range crds.Items {
+		if before.crds[crd.Name] {
+			continue
+		}
+		newCRDs = append(newCRDs, crd)
+		newCRDOwners[crd.UID] = crd.Name
+	}
+
+	for _, gvk := range residueKinds {
+		items, err := listResources(ctx, options.client, gvk, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if before.objects[item.GetUID()] {
+				continue
+			}
+			if reason := attributeToOperator(item, options, newCRDOwners); reason != "" {
+				leftovers = append(leftovers, newLeftover(item, reason))
+			}
+		}
+	}
+
+	for _, crd := range newCRDs {
+		for _, version := range crd.Spec.Versions {
+			if !version.Storage {
+				continue
+			}
+			gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: version.Name, Kind: crd.Spec.Names.Kind}
+			items, err := listResources(ctx, options.client, gvk, "")
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range items {
+				leftovers = append(leftovers, newLeftover(item, "instance of CRD "+crd.Name))
+			}
+		}
+	}
+
+	return leftovers, nil
+}
+
+// attributeToOperator returns why an object is considered to be created by the operator under audit,
+// or an empty string when it is not. Namespaces, owner references and OLM labels are trusted first,
+// the package name showing up in the object name is only a last resort.
+func attributeToOperator(obj unstructured.Unstructured, options auditOptions, newCRDOwners map[types.UID]string) string {
+	if obj.GetNamespace() == options.namespace {
+		return "in operator namespace"
+	}
+	for _, ns := range auditNamespaces(options)[1:] {
+		if obj.GetNamespace() == ns {
+			return "in target namespace"
+		}
+	}
+
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.Kind == "ClusterServiceVersion" {
+			return "owned by CSV " + ref.Name
+		}
+		if name, ok := newCRDOwners[ref.UID]; ok {
+			return "owned by CRD " + name
+		}
+	}
+
+	labels := obj.GetLabels()
+	if labels["olm.owner"] != "" && labels["olm.owner.namespace"] == options.namespace {
+		return "owned by OLM for operator namespace"
+	}
+	if _, ok := labels["operators.coreos.com/"+options.subscription.Package+"."+options.namespace]; ok {
+		return "labeled with operator package"
+	}
+	if options.subscription.Package != "" && strings.Contains(obj.GetName(), options.subscription.Package) {
+		return "named after operator package"
+	}
+
+	return ""
+}
+
+func newLeftover(obj unstructured.Unstructured, reason string) report.Leftover {
+	return report.Leftover{
+		Kind:      obj.GetKind(),
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		Reason:    reason,
+	}
+}
+
+func waitForNamespacesDeletion(ctx context.Context, options auditOptions, namespaces []string) error {
+	return wait.PollImmediateWithContext(ctx, time.Second, options.csvWaitTime, func(ctx context.Context) (bool, error) {
+		for _, name := range namespaces {
+			ns := &unstructured.Unstructured{}
+			ns.SetAPIVersion("v1")
+			ns.SetKind("Namespace")
+			err := options.client.GetUnstructured(ctx, "", name, ns)
+			if err == nil {
+				return false, nil
+			}
+			if !apierrors.IsNotFound(err) {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+}
@@ -0,0 +1,93 @@
+package capability
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opdev/opcap/internal/operator"
+	"github.com/spf13/afero"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("OperatorResidue audit", func() {
+	newClusterRole := func(name string, labels map[string]string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("rbac.authorization.k8s.io/v1")
+		obj.SetKind("ClusterRole")
+		obj.SetName(name)
+		obj.SetUID(types.UID(name))
+		obj.SetLabels(labels)
+		return obj
+	}
+
+	It("should report only the resources left behind by the operator", func() {
+		client := operator.NewFakeOpClient(newClusterRole("existing-testpackage-role", nil))
+		fs := afero.NewMemMapFs()
+		output := &bytes.Buffer{}
+
+		auditFn, cleanupFn := operatorResidue(context.TODO(),
+			withClient(client),
+			withNamespace("opcap-testpackage"),
+			withSubscription(&operator.SubscriptionData{Package: "testpackage"}),
+			withOperatorGroupData(&operator.OperatorGroupData{}),
+			withTimeout(time.Second),
+			withFilesystem(fs),
+			withReportWriter(output),
+		)
+		Expect(auditFn(context.TODO())).To(Succeed())
+
+		// what the operator creates while it is installed
+		Expect(client.CreateUnstructured(context.TODO(), newClusterRole("testpackage-manager", nil))).To(Succeed())
+		Expect(client.CreateUnstructured(context.TODO(), newClusterRole("webhook-reader", map[string]string{"olm.owner": "testpackage.v1.0.0", "olm.owner.namespace": "opcap-testpackage"}))).To(Succeed())
+		Expect(client.CreateUnstructured(context.TODO(), newClusterRole("metrics-reader", map[string]string{"operators.coreos.com/testpackage.opcap-testpackage": ""}))).To(Succeed())
+		csvOwned := newClusterRole("proxy-role", nil)
+		csvOwned.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "operators.coreos.com/v1alpha1", Kind: "ClusterServiceVersion", Name: "testpackage.v1.0.0", UID: "csv"}})
+		Expect(client.CreateUnstructured(context.TODO(), csvOwned)).To(Succeed())
+		Expect(client.CreateUnstructured(context.TODO(), newClusterRole("namespace-only", map[string]string{"olm.owner.namespace": "opcap-testpackage"}))).To(Succeed())
+		Expect(client.CreateUnstructured(context.TODO(), newClusterRole("unrelated", nil))).To(Succeed())
+
+		crdObject, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&apiextensionsv1.CustomResourceDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition"},
+			ObjectMeta: metav1.ObjectMeta{Name: "examples.example.com"},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: "example.com",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: "Example"},
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{Name: "v1", Served: true, Storage: true},
+				},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.CreateUnstructured(context.TODO(), &unstructured.Unstructured{Object: crdObject})).To(Succeed())
+
+		instance := &unstructured.Unstructured{}
+		instance.SetAPIVersion("example.com/v1")
+		instance.SetKind("Example")
+		instance.SetName("leftover")
+		instance.SetNamespace("otherns")
+		Expect(client.CreateUnstructured(context.TODO(), instance)).To(Succeed())
+
+		Expect(cleanupFn(context.TODO())).To(Succeed())
+
+		Expect(output.String()).To(ContainSubstring("Result: residue found"))
+		Expect(output.String()).To(ContainSubstring("Leftover: ClusterRole testpackage-manager (named after operator package)"))
+		Expect(output.String()).To(ContainSubstring("Leftover: ClusterRole webhook-reader (owned by OLM for operator namespace)"))
+		Expect(output.String()).To(ContainSubstring("Leftover: Example otherns/leftover (instance of CRD examples.example.com)"))
+		Expect(output.String()).To(ContainSubstring("Leftover: ClusterRole metrics-reader (labeled with operator package)"))
+		Expect(output.String()).To(ContainSubstring("Leftover: ClusterRole proxy-role (owned by CSV testpackage.v1.0.0)"))
+		Expect(output.String()).ToNot(ContainSubstring("unrelated"))
+		Expect(output.String()).ToNot(ContainSubstring("namespace-only"))
+		Expect(output.String()).ToNot(ContainSubstring("existing-testpackage-role"))
+
+		report, err := afero.ReadFile(fs, "operator_residue_report.json")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(report)).To(ContainSubstring(`"message":"residue"`))
+	})
+})
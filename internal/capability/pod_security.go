@@ -0,0 +1,250 @@
+package capability
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/opdev/opcap/internal/logger"
+	"github.com/opdev/opcap/internal/report"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// sccAnnotation is set by OpenShift to the SecurityContextConstraints a pod was admitted under
+const sccAnnotation = "openshift.io/scc"
+
+// baselineCapabilities are the capabilities the baseline Pod Security Standard allows to add
+var baselineCapabilities = map[corev1.Capability]bool{
+	"AUDIT_WRITE":      true,
+	"CHOWN":            true,
+	"DAC_OVERRIDE":     true,
+	"FOWNER":           true,
+	"FSETID":           true,
+	"KILL":             true,
+	"MKNOD":            true,
+	"NET_BIND_SERVICE": true,
+	"SETFCAP":          true,
+	"SETGID":           true,
+	"SETPCAP":          true,
+	"SETUID":           true,
+	"SYS_CHROOT":       true,
+}
+
+// baselineSysctls are the sysctls the baseline Pod Security Standard allows to set
+var baselineSysctls = map[string]bool{
+	"kernel.shm_rmid_forced":              true,
+	"net.ipv4.ip_local_port_range":        true,
+	"net.ipv4.ip_unprivileged_port_start": true,
+	"net.ipv4.tcp_syncookies":             true,
+	"net.ipv4.ping_group_range":           true,
+}
+
+// baselineSELinuxTypes are the SELinux types the baseline Pod Security Standard allows to set
+var baselineSELinuxTypes = map[string]bool{
+	"":                 true,
+	"container_t":      true,
+	"container_init_t": true,
+	"container_kvm_t":  true,
+}
+
+// podSecurity inspects the operator and operand pods running in the audit namespaces: the SCC they were admitted
+// under, their host namespaces, privileges, capabilities, sysctls, seccomp, SELinux and AppArmor profiles. Each
+// pod is evaluated against the Pod Security Standards and reported with the most restrictive level it complies with.
+func podSecurity(ctx context.Context, opts ...auditOption) (auditFn, auditCleanupFn) {
+	var options auditOptions
+	for _, opt := range opts {
+		err := opt(&options)
+		if err != nil {
+			return func(_ context.Context) error {
+					return fmt.Errorf("option failed: %v", err)
+				}, func(_ context.Context) error {
+					return nil
+				}
+		}
+	}
+
+	return func(ctx context.Context) error {
+		logger.Debugw("auditing pod security for operator", "package", options.subscription.Package, "channel", options.subscription.Channel, "installmode", options.subscription.InstallModeType)
+
+		pods, err := auditPods(ctx, options)
+		if err != nil {
+			return err
+		}
+
+		results := []report.PodSecurityResult{}
+		for _, pod := range pods {
+			results = append(results, evaluatePodSecurity(pod))
+		}
+
+		file, err := options.fs.OpenFile("pod_security_report.json", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		data := report.TemplateData{
+			OcpVersion:         options.ocpVersion,
+			Subscription:       *options.subscription,
+			PodSecurityResults: results,
+		}
+
+		if err := report.PodSecurityJsonReport(file, data); err != nil {
+			return fmt.Errorf("could not generate pod security JSON report: %v", err)
+		}
+
+		if err := report.PodSecurityTextReport(options.reportWriter, data); err != nil {
+			return fmt.Errorf("could not generate pod security text report: %v", err)
+		}
+
+		return nil
+	}, func(_ context.Context) error { return nil }
+}
+
+// evaluatePodSecurity checks a pod against the baseline and restricted Pod Security Standards
+func evaluatePodSecurity(pod corev1.Pod) report.PodSecurityResult {
+	result := report.PodSecurityResult{
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+		SCC:       pod.Annotations[sccAnnotation],
+	}
+
+	baseline := []string{}
+	restricted := []string{}
+
+	if pod.Spec.HostNetwork {
+		baseline = append(baseline, "hostNetwork is true")
+	}
+	if pod.Spec.HostPID {
+		baseline = append(baseline, "hostPID is true")
+	}
+	if pod.Spec.HostIPC {
+		baseline = append(baseline, "hostIPC is true")
+	}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.HostPath != nil {
+			baseline = append(baseline, fmt.Sprintf("volume %s is a hostPath", volume.Name))
+		}
+	}
+
+	podContext := pod.Spec.SecurityContext
+	if podContext == nil {
+		podContext = &corev1.PodSecurityContext{}
+	}
+	if podContext.SeccompProfile != nil && podContext.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+		baseline = append(baseline, "pod sets an Unconfined seccomp profile")
+	}
+	for _, sysctl := range podContext.Sysctls {
+		if !baselineSysctls[sysctl.Name] {
+			baseline = append(baseline, fmt.Sprintf("pod sets unsafe sysctl %s", sysctl.Name))
+		}
+	}
+	baseline = append(baseline, seLinuxViolations("pod", podContext.SELinuxOptions)...)
+
+	containers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for _, container := range pod.Spec.EphemeralContainers {
+		containers = append(containers, corev1.Container(container.EphemeralContainerCommon))
+	}
+	for _, container := range containers {
+		securityContext := container.SecurityContext
+		if securityContext == nil {
+			securityContext = &corev1.SecurityContext{}
+		}
+		prefix := "container " + container.Name
+
+		if securityContext.Privileged != nil && *securityContext.Privileged {
+			baseline = append(baseline, prefix+" is privileged")
+		}
+		for _, port := range container.Ports {
+			if port.HostPort != 0 {
+				baseline = append(baseline, fmt.Sprintf("%s uses host port %d", prefix, port.HostPort))
+			}
+		}
+		if securityContext.SeccompProfile != nil && securityContext.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+			baseline = append(baseline, prefix+" sets an Unconfined seccomp profile")
+		}
+		if securityContext.ProcMount != nil && *securityContext.ProcMount != corev1.DefaultProcMount {
+			baseline = append(baseline, fmt.Sprintf("%s sets procMount %s", prefix, *securityContext.ProcMount))
+		}
+		baseline = append(baseline, seLinuxViolations(prefix, securityContext.SELinuxOptions)...)
+		if profile := pod.Annotations[corev1.AppArmorBetaContainerAnnotationKeyPrefix+container.Name]; profile == corev1.AppArmorBetaProfileNameUnconfined {
+			baseline = append(baseline, prefix+" sets an unconfined AppArmor profile")
+		}
+
+		if securityContext.AllowPrivilegeEscalation == nil || *securityContext.AllowPrivilegeEscalation {
+			restricted = append(restricted, prefix+" allows privilege escalation")
+		}
+
+		runAsNonRoot := podContext.RunAsNonRoot
+		if securityContext.RunAsNonRoot != nil {
+			runAsNonRoot = securityContext.RunAsNonRoot
+		}
+		if runAsNonRoot == nil || !*runAsNonRoot {
+			restricted = append(restricted, prefix+" does not set runAsNonRoot")
+		}
+		runAsUser := podContext.RunAsUser
+		if securityContext.RunAsUser != nil {
+			runAsUser = securityContext.RunAsUser
+		}
+		if runAsUser != nil && *runAsUser == 0 {
+			restricted = append(restricted, prefix+" runs as root")
+		}
+
+		seccompProfile := podContext.SeccompProfile
+		if securityContext.SeccompProfile != nil {
+			seccompProfile = securityContext.SeccompProfile
+		}
+		if seccompProfile == nil || (seccompProfile.Type != corev1.SeccompProfileTypeRuntimeDefault && seccompProfile.Type != corev1.SeccompProfileTypeLocalhost) {
+			restricted = append(restricted, prefix+" does not set a RuntimeDefault or Localhost seccomp profile")
+		}
+
+		dropsAll := false
+		if securityContext.Capabilities != nil {
+			for _, capability := range securityContext.Capabilities.Add {
+				if !baselineCapabilities[capability] {
+					baseline = append(baseline, fmt.Sprintf("%s adds capability %s", prefix, capability))
+				} else if capability != "NET_BIND_SERVICE" {
+					restricted = append(restricted, fmt.Sprintf("%s adds capability %s", prefix, capability))
+				}
+			}
+			for _, capability := range securityContext.Capabilities.Drop {
+				if capability == "ALL" {
+					dropsAll = true
+				}
+			}
+		}
+		if !dropsAll {
+			restricted = append(restricted, prefix+" does not drop ALL capabilities")
+		}
+	}
+
+	result.Violations = append(baseline, restricted...)
+	switch {
+	case len(baseline) > 0:
+		result.Level = report.PodSecurityPrivileged
+	case len(restricted) > 0:
+		result.Level = report.PodSecurityBaseline
+	default:
+		result.Level = report.PodSecurityRestricted
+	}
+
+	return result
+}
+
+// seLinuxViolations lists the SELinux options the baseline Pod Security Standard forbids: a custom type, user or role
+func seLinuxViolations(prefix string, options *corev1.SELinuxOptions) []string {
+	violations := []string{}
+	if options == nil {
+		return violations
+	}
+	if !baselineSELinuxTypes[options.Type] {
+		violations = append(violations, fmt.Sprintf("%s sets SELinux type %s", prefix, options.Type))
+	}
+	if options.User != "" {
+		violations = append(violations, fmt.Sprintf("%s sets SELinux user %s", prefix, options.User))
+	}
+	if options.Role != "" {
+		violations = append(violations, fmt.Sprintf("%s sets SELinux role %s", prefix, options.Role))
+	}
+	return violations
+}
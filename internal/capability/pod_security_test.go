@@ -0,0 +1,142 @@
+package capability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opdev/opcap/internal/operator"
+	"github.com/opdev/opcap/internal/report"
+	"github.com/spf13/afero"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("PodSecurity audit", func() {
+	yes, no := true, false
+
+	restrictedPod := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "testns",
+				Annotations: map[string]string{sccAnnotation: "restricted-v2"},
+			},
+			Spec: corev1.PodSpec{
+				SecurityContext: &corev1.PodSecurityContext{
+					RunAsNonRoot:   &yes,
+					SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+				},
+				Containers: []corev1.Container{{
+					Name: "manager",
+					SecurityContext: &corev1.SecurityContext{
+						AllowPrivilegeEscalation: &no,
+						Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+					},
+				}},
+			},
+		}
+	}
+
+	Context("evaluating a pod", func() {
+		It("should accept a restricted pod", func() {
+			result := evaluatePodSecurity(*restrictedPod("operator"))
+			Expect(result.Level).To(Equal(report.PodSecurityRestricted))
+			Expect(result.SCC).To(Equal("restricted-v2"))
+			Expect(result.Violations).To(BeEmpty())
+		})
+		It("should rate a pod allowing privilege escalation as baseline", func() {
+			pod := restrictedPod("operator")
+			pod.Spec.Containers[0].SecurityContext.AllowPrivilegeEscalation = nil
+			pod.Spec.Containers[0].SecurityContext.Capabilities.Add = []corev1.Capability{"NET_BIND_SERVICE", "CHOWN"}
+			result := evaluatePodSecurity(*pod)
+			Expect(result.Level).To(Equal(report.PodSecurityBaseline))
+			Expect(result.Violations).To(ConsistOf(
+				"container manager allows privilege escalation",
+				"container manager adds capability CHOWN",
+			))
+		})
+		It("should rate a pod using host namespaces as privileged", func() {
+			pod := restrictedPod("operand")
+			pod.Spec.HostNetwork = true
+			pod.Spec.Containers[0].SecurityContext.Privileged = &yes
+			pod.Spec.Containers[0].SecurityContext.Capabilities.Add = []corev1.Capability{"SYS_ADMIN"}
+			result := evaluatePodSecurity(*pod)
+			Expect(result.Level).To(Equal(report.PodSecurityPrivileged))
+			Expect(result.Violations).To(ConsistOf(
+				"hostNetwork is true",
+				"container manager is privileged",
+				"container manager adds capability SYS_ADMIN",
+			))
+		})
+		It("should rate a pod loosening its kernel confinement as privileged", func() {
+			pod := restrictedPod("operand")
+			pod.Annotations[corev1.AppArmorBetaContainerAnnotationKeyPrefix+"manager"] = corev1.AppArmorBetaProfileNameUnconfined
+			pod.Spec.SecurityContext.Sysctls = []corev1.Sysctl{{Name: "net.ipv4.tcp_syncookies", Value: "1"}, {Name: "kernel.msgmax", Value: "65536"}}
+			pod.Spec.SecurityContext.SELinuxOptions = &corev1.SELinuxOptions{Type: "spc_t", Level: "s0:c123,c456"}
+			unmasked := corev1.UnmaskedProcMount
+			pod.Spec.Containers[0].SecurityContext.ProcMount = &unmasked
+			pod.Spec.Containers[0].SecurityContext.SELinuxOptions = &corev1.SELinuxOptions{Type: "container_t", User: "system_u", Role: "system_r"}
+			result := evaluatePodSecurity(*pod)
+			Expect(result.Level).To(Equal(report.PodSecurityPrivileged))
+			Expect(result.Violations).To(ConsistOf(
+				"pod sets unsafe sysctl kernel.msgmax",
+				"pod sets SELinux type spc_t",
+				"container manager sets procMount Unmasked",
+				"container manager sets SELinux user system_u",
+				"container manager sets SELinux role system_r",
+				"container manager sets an unconfined AppArmor profile",
+			))
+		})
+		It("should check seccomp profiles and ephemeral containers", func() {
+			pod := restrictedPod("operand")
+			pod.Spec.EphemeralContainers = []corev1.EphemeralContainer{{
+				EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+					Name: "debugger",
+					SecurityContext: &corev1.SecurityContext{
+						AllowPrivilegeEscalation: &no,
+						Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+						SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeUnconfined},
+					},
+				},
+			}}
+			result := evaluatePodSecurity(*pod)
+			Expect(result.Level).To(Equal(report.PodSecurityPrivileged))
+			Expect(result.Violations).To(ConsistOf(
+				"container debugger sets an Unconfined seccomp profile",
+				"container debugger does not set a RuntimeDefault or Localhost seccomp profile",
+			))
+		})
+	})
+
+	Context("running the audit", func() {
+		It("should report every pod", func() {
+			pod := restrictedPod("operand")
+			pod.Annotations = nil
+			pod.Spec.SecurityContext = nil
+
+			fs := afero.NewMemMapFs()
+			output := &bytes.Buffer{}
+			auditFn, _ := podSecurity(context.TODO(),
+				withClient(operator.NewFakeOpClient(restrictedPod("operator"), pod)),
+				withNamespace("testns"),
+				withSubscription(&operator.SubscriptionData{Package: "testpackage"}),
+				withFilesystem(fs),
+				withReportWriter(output),
+			)
+			Expect(auditFn(context.TODO())).To(Succeed())
+
+			Expect(output.String()).To(ContainSubstring("Pod: testns/operator\nSCC: restricted-v2\nPod Security Level: restricted"))
+			Expect(output.String()).To(ContainSubstring("Pod: testns/operand\nSCC: none\nPod Security Level: baseline"))
+			Expect(output.String()).To(ContainSubstring("Violation: container manager does not set runAsNonRoot"))
+
+			report, err := afero.ReadFile(fs, "pod_security_report.json")
+			Expect(err).ToNot(HaveOccurred())
+			for _, line := range bytes.Split(bytes.TrimSpace(report), []byte("\n")) {
+				Expect(json.Valid(line)).To(BeTrue())
+			}
+		})
+	})
+})
@@ -0,0 +1,146 @@
+package capability
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/opdev/opcap/internal/logger"
+	"github.com/opdev/opcap/internal/report"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var prometheusRuleKind = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PrometheusRule"}
+
+// serviceDownExpr matches the usual shapes of a service-down alert expression: up == 0, absent(up{...}) and the like
+var serviceDownExpr = regexp.MustCompile(`(^|[^a-zA-Z0-9_:])(up\b|absent\()`)
+
+// prometheusRules collects the PrometheusRules created by the operator or its operands and checks
+// that every alert is documented well enough to be acted upon
+func prometheusRules(ctx context.Context, opts ...auditOption) (auditFn, auditCleanupFn) {
+	var options auditOptions
+	for _, opt := range opts {
+		err := opt(&options)
+		if err != nil {
+			return func(_ context.Context) error {
+					return fmt.Errorf("option failed: %v", err)
+				}, func(_ context.Context) error {
+					return nil
+				}
+		}
+	}
+
+	return func(ctx context.Context) error {
+		logger.Debugw("auditing prometheus rules for operator", "package", options.subscription.Package, "channel", options.subscription.Channel, "installmode", options.subscription.InstallModeType)
+
+		result := report.PrometheusRulesResult{}
+		for _, ns := range auditNamespaces(options) {
+			rules, err := listResources(ctx, options.client, prometheusRuleKind, ns)
+			if err != nil {
+				return err
+			}
+			for _, rule := range rules {
+				result.Rules = append(result.Rules, rule.GetNamespace()+"/"+rule.GetName())
+				result.Alerts = append(result.Alerts, checkAlerts(rule)...)
+			}
+		}
+
+		for _, alert := range result.Alerts {
+			if alert.ServiceDown {
+				result.ServiceDownAlert = true
+			}
+			if alert.Complete() {
+				result.Coverage++
+			}
+		}
+		if len(result.Alerts) > 0 {
+			result.Coverage = result.Coverage * 100 / len(result.Alerts)
+		}
+
+		file, err := options.fs.OpenFile("prometheus_rules_report.json", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		data := report.TemplateData{
+			OcpVersion:      options.ocpVersion,
+			Subscription:    *options.subscription,
+			PrometheusRules: result,
+		}
+
+		if err := report.PrometheusRulesJsonReport(file, data); err != nil {
+			return fmt.Errorf("could not generate prometheus rules JSON report: %v", err)
+		}
+
+		if err := report.PrometheusRulesTextReport(options.reportWriter, data); err != nil {
+			return fmt.Errorf("could not generate prometheus rules text report: %v", err)
+		}
+
+		return nil
+	}, func(_ context.Context) error { return nil }
+}
+
+// checkAlerts checks every alerting rule of a PrometheusRule. Recording rules are skipped.
+func checkAlerts(rule unstructured.Unstructured) []report.AlertCheck {
+	checks := []report.AlertCheck{}
+
+	groups, _, _ := unstructured.NestedSlice(rule.Object, "spec", "groups")
+	for _, g := range groups {
+		group, ok := g.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		groupName, _, _ := unstructured.NestedString(group, "name")
+		rules, _, _ := unstructured.NestedSlice(group, "rules")
+		for _, r := range rules {
+			alertRule, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			alert, _, _ := unstructured.NestedString(alertRule, "alert")
+			if alert == "" {
+				continue
+			}
+
+			labels, _, _ := unstructured.NestedStringMap(alertRule, "labels")
+			annotations, _, _ := unstructured.NestedStringMap(alertRule, "annotations")
+			expr := ""
+			if e, ok, _ := unstructured.NestedFieldNoCopy(alertRule, "expr"); ok && e != nil {
+				expr = fmt.Sprint(e)
+			}
+
+			check := report.AlertCheck{
+				Rule:           rule.GetName(),
+				Group:          groupName,
+				Alert:          alert,
+				Severity:       labels["severity"],
+				HasSummary:     annotations["summary"] != "" || annotations["message"] != "",
+				HasDescription: annotations["description"] != "",
+				HasRunbook:     annotations["runbook_url"] != "",
+				Expr:           expr,
+				ServiceDown:    labels["severity"] == "critical" && serviceDownExpr.MatchString(expr),
+			}
+			if err := validatePromQL(expr); err != nil {
+				check.ExprError = err.Error()
+			}
+			checks = append(checks, check)
+		}
+	}
+
+	return checks
+}
+
+// validatePromQL parses a PromQL expression with the Prometheus parser and returns its syntax error
+func validatePromQL(expr string) error {
+	if strings.TrimSpace(expr) == "" {
+		return fmt.Errorf("empty expression")
+	}
+	_, err := parser.ParseExpr(expr)
+	return err
+}
@@ -0,0 +1,100 @@
+package capability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opdev/opcap/internal/operator"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("PrometheusRules audit", func() {
+	Context("validating PromQL", func() {
+		DescribeTable("valid expressions",
+			func(expr string) {
+				Expect(validatePromQL(expr)).To(Succeed())
+			},
+			Entry("service down", `up{job="operator"} == 0`),
+			Entry("absent", `absent(up{job="operator"})`),
+			Entry("rate", `sum by (controller) (rate(controller_runtime_reconcile_errors_total[5m])) > 0`),
+			Entry("brackets in strings", `foo{path="/(a|b"}`),
+			Entry("metric ending with a keyword", `cpu_vendor`),
+		)
+		DescribeTable("invalid expressions",
+			func(expr string) {
+				Expect(validatePromQL(expr)).ToNot(Succeed())
+			},
+			Entry("empty", ""),
+			Entry("unclosed parenthesis", `sum(rate(foo[5m])`),
+			Entry("mismatched bracket", `rate(foo[5m)]`),
+			Entry("unterminated string", `foo{job="operator}`),
+			Entry("dangling operator", `up ==`),
+			Entry("dangling keyword", `up == 0 and`),
+			Entry("grouping without parentheses", `sum by foo`),
+			Entry("unknown operator", `up ==== 0`),
+			Entry("range after a function call", `rate(x)[5m`),
+		)
+	})
+
+	Context("running the audit", func() {
+		It("should report undocumented alerts and the coverage", func() {
+			rule := &unstructured.Unstructured{Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"groups": []interface{}{map[string]interface{}{
+						"name": "operator.rules",
+						"rules": []interface{}{
+							map[string]interface{}{
+								"alert":  "OperatorDown",
+								"expr":   `absent(up{job="operator"} == 1)`,
+								"labels": map[string]interface{}{"severity": "critical"},
+								"annotations": map[string]interface{}{
+									"summary":     "Operator is down",
+									"description": "The operator has disappeared",
+									"runbook_url": "https://example.com/runbooks/OperatorDown.md",
+								},
+							},
+							map[string]interface{}{
+								"alert": "ReconcileErrors",
+								"expr":  `rate(reconcile_errors_total[5m] > 0`,
+							},
+							map[string]interface{}{
+								"record": "job:reconcile_errors:rate5m",
+								"expr":   `rate(reconcile_errors_total[5m])`,
+							},
+						},
+					}},
+				},
+			}}
+			rule.SetAPIVersion("monitoring.coreos.com/v1")
+			rule.SetKind("PrometheusRule")
+			rule.SetName("operator-rules")
+			rule.SetNamespace("testns")
+
+			fs := afero.NewMemMapFs()
+			output := &bytes.Buffer{}
+			auditFn, _ := prometheusRules(context.TODO(),
+				withClient(operator.NewFakeOpClient(rule)),
+				withNamespace("testns"),
+				withSubscription(&operator.SubscriptionData{Package: "testpackage"}),
+				withFilesystem(fs),
+				withReportWriter(output),
+			)
+			Expect(auditFn(context.TODO())).To(Succeed())
+
+			Expect(output.String()).To(ContainSubstring("PrometheusRules: testns/operator-rules"))
+			Expect(output.String()).To(ContainSubstring("Alerts: 2"))
+			Expect(output.String()).To(ContainSubstring("Service Down Alert: true"))
+			Expect(output.String()).To(ContainSubstring("Coverage: 50%"))
+			Expect(output.String()).To(ContainSubstring("Alert: ReconcileErrors (operator-rules/operator.rules)\n  missing severity label\n  missing summary annotation\n  missing description annotation\n  missing runbook_url annotation\n  invalid expression"))
+			Expect(output.String()).ToNot(ContainSubstring("Alert: OperatorDown"))
+
+			report, err := afero.ReadFile(fs, "prometheus_rules_report.json")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(json.Valid(report)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,195 @@
+package capability
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/opdev/opcap/internal/logger"
+	"github.com/opdev/opcap/internal/report"
+	operatorv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+// privilegeWeights is how much each kind of finding adds to the privilege score, which is capped at 100
+var privilegeWeights = map[string]int{
+	report.RBACClusterAdmin:         100,
+	report.RBACWildcardVerbs:        10,
+	report.RBACWildcardResources:    10,
+	report.RBACWildcardAPIGroups:    10,
+	report.RBACClusterWideSecrets:   25,
+	report.RBACEscalationVerb:       25,
+	report.RBACWildcardNonResources: 10,
+}
+
+// escalationVerbs let a subject grant itself, or act as, someone with more permissions
+var escalationVerbs = []string{"escalate", "bind", "impersonate"}
+
+// secretsReadVerbs are the verbs that expose the content of secrets
+var secretsReadVerbs = []string{"get", "list", "watch", "*"}
+
+// rbacPrivilege statically analyses the permissions and cluster permissions requested by the CSV and flags rules
+// that go beyond least privilege: wildcards, cluster-admin equivalent grants, cluster-wide secrets access and
+// verbs allowing privilege escalation. The findings add up to a privilege score from 0 to 100.
+func rbacPrivilege(ctx context.Context, opts ...auditOption) (auditFn, auditCleanupFn) {
+	var options auditOptions
+	for _, opt := range opts {
+		err := opt(&options)
+		if err != nil {
+			return func(_ context.Context) error {
+					return fmt.Errorf("option failed: %v", err)
+				}, func(_ context.Context) error {
+					return nil
+				}
+		}
+	}
+
+	return func(ctx context.Context) error {
+		logger.Debugw("analysing RBAC for operator", "package", options.subscription.Package, "channel", options.subscription.Channel, "installmode", options.subscription.InstallModeType)
+
+		csv, err := packageCSV(ctx, options)
+		if err != nil {
+			return fmt.Errorf("could not get CSV: %v", err)
+		}
+		if csv == nil {
+			return fmt.Errorf("exiting RBACPrivilege since no CSV was found for package %s", options.subscription.Package)
+		}
+
+		result := analyseCSVPermissions(csv)
+
+		file, err := options.fs.OpenFile("rbac_privilege_report.json", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		data := report.TemplateData{
+			OcpVersion:    options.ocpVersion,
+			Subscription:  *options.subscription,
+			Csv:           csv,
+			RBACPrivilege: result,
+		}
+
+		if err := report.RBACPrivilegeJsonReport(file, data); err != nil {
+			return fmt.Errorf("could not generate RBAC privilege JSON report: %v", err)
+		}
+
+		if err := report.RBACPrivilegeTextReport(options.reportWriter, data); err != nil {
+			return fmt.Errorf("could not generate RBAC privilege text report: %v", err)
+		}
+
+		return nil
+	}, func(_ context.Context) error { return nil }
+}
+
+// analyseCSVPermissions checks every rule of the CSV install strategy and scores the findings
+func analyseCSVPermissions(csv *operatorv1alpha1.ClusterServiceVersion) report.RBACPrivilegeResult {
+	result := report.RBACPrivilegeResult{}
+	strategy := csv.Spec.InstallStrategy.StrategySpec
+
+	for _, permission := range strategy.Permissions {
+		for _, rule := range permission.Rules {
+			result.Findings = append(result.Findings, analyseRule(report.RBACNamespaceScope, permission.ServiceAccountName, rule)...)
+		}
+	}
+	for _, permission := range strategy.ClusterPermissions {
+		for _, rule := range permission.Rules {
+			result.Findings = append(result.Findings, analyseRule(report.RBACClusterScope, permission.ServiceAccountName, rule)...)
+		}
+	}
+
+	for _, finding := range result.Findings {
+		result.Score += privilegeWeights[finding.Issue]
+	}
+	if result.Score > 100 {
+		result.Score = 100
+	}
+
+	return result
+}
+
+func analyseRule(scope, serviceAccount string, rule rbacv1.PolicyRule) []report.RBACFinding {
+	findings := []report.RBACFinding{}
+	flag := func(issue string) {
+		findings = append(findings, report.RBACFinding{
+			Scope:          scope,
+			ServiceAccount: serviceAccount,
+			Rule:           formatRule(rule),
+			Issue:          issue,
+		})
+	}
+
+	allVerbs := contains(rule.Verbs, "*")
+	allResources := contains(rule.Resources, "*")
+	allGroups := contains(rule.APIGroups, "*")
+
+	if scope == report.RBACClusterScope && allVerbs && allResources && allGroups {
+		// nothing else is worth mentioning about a cluster-admin equivalent rule
+		flag(report.RBACClusterAdmin)
+		return findings
+	}
+
+	if allVerbs {
+		flag(report.RBACWildcardVerbs)
+	}
+	if allResources {
+		flag(report.RBACWildcardResources)
+	}
+	if allGroups {
+		flag(report.RBACWildcardAPIGroups)
+	}
+	if contains(rule.NonResourceURLs, "*") {
+		flag(report.RBACWildcardNonResources)
+	}
+
+	coreGroup := contains(rule.APIGroups, "") || allGroups
+	secrets := (contains(rule.Resources, "secrets") || allResources) && len(rule.ResourceNames) == 0
+	if scope == report.RBACClusterScope && coreGroup && secrets && containsAny(rule.Verbs, secretsReadVerbs) {
+		flag(report.RBACClusterWideSecrets)
+	}
+
+	if containsAny(rule.Verbs, escalationVerbs) {
+		flag(report.RBACEscalationVerb)
+	}
+
+	return findings
+}
+
+// formatRule prints a policy rule the way it reads in a manifest, e.g. apiGroups=[""] resources=[secrets] verbs=[get list]
+func formatRule(rule rbacv1.PolicyRule) string {
+	parts := []string{}
+	if len(rule.NonResourceURLs) > 0 {
+		parts = append(parts, fmt.Sprintf("nonResourceURLs=%v", rule.NonResourceURLs))
+	} else {
+		groups := make([]string, 0, len(rule.APIGroups))
+		for _, group := range rule.APIGroups {
+			groups = append(groups, fmt.Sprintf("%q", group))
+		}
+		parts = append(parts, fmt.Sprintf("apiGroups=[%s]", strings.Join(groups, " ")), fmt.Sprintf("resources=%v", rule.Resources))
+	}
+	if len(rule.ResourceNames) > 0 {
+		parts = append(parts, fmt.Sprintf("resourceNames=%v", rule.ResourceNames))
+	}
+	parts = append(parts, fmt.Sprintf("verbs=%v", rule.Verbs))
+	return strings.Join(parts, " ")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAny(values []string, candidates []string) bool {
+	for _, candidate := range candidates {
+		if contains(values, candidate) {
+			return true
+		}
+	}
+	return false
+}
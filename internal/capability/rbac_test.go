@@ -0,0 +1,99 @@
+package capability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opdev/opcap/internal/operator"
+	"github.com/opdev/opcap/internal/report"
+	operatorv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/spf13/afero"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("RBACPrivilege audit", func() {
+	newCSV := func(permissions, clusterPermissions []rbacv1.PolicyRule) *operatorv1alpha1.ClusterServiceVersion {
+		return &operatorv1alpha1.ClusterServiceVersion{
+			ObjectMeta: metav1.ObjectMeta{Name: "testpackage.v1.0.0", Namespace: "testns"},
+			Spec: operatorv1alpha1.ClusterServiceVersionSpec{
+				InstallStrategy: operatorv1alpha1.NamedInstallStrategy{
+					StrategySpec: operatorv1alpha1.StrategyDetailsDeployment{
+						Permissions:        []operatorv1alpha1.StrategyDeploymentPermissions{{ServiceAccountName: "operator", Rules: permissions}},
+						ClusterPermissions: []operatorv1alpha1.StrategyDeploymentPermissions{{ServiceAccountName: "operator", Rules: clusterPermissions}},
+					},
+				},
+			},
+		}
+	}
+	issues := func(result report.RBACPrivilegeResult) []string {
+		found := []string{}
+		for _, finding := range result.Findings {
+			found = append(found, finding.Scope+": "+finding.Issue)
+		}
+		return found
+	}
+
+	Context("analysing permissions", func() {
+		It("should score least privilege as low", func() {
+			result := analyseCSVPermissions(newCSV(
+				[]rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets", "configmaps"}, Verbs: []string{"get", "list", "watch"}}},
+				[]rbacv1.PolicyRule{{APIGroups: []string{"example.com"}, Resources: []string{"examples"}, Verbs: []string{"get", "update"}}},
+			))
+			Expect(result.Findings).To(BeEmpty())
+			Expect(result.Score).To(Equal(0))
+			Expect(result.Level()).To(Equal("low"))
+		})
+		It("should flag a cluster-admin equivalent grant", func() {
+			result := analyseCSVPermissions(newCSV(nil,
+				[]rbacv1.PolicyRule{{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}}},
+			))
+			Expect(issues(result)).To(ConsistOf("cluster: " + report.RBACClusterAdmin))
+			Expect(result.Score).To(Equal(100))
+			Expect(result.Level()).To(Equal("high"))
+		})
+		It("should flag wildcards, cluster-wide secrets and escalation verbs", func() {
+			result := analyseCSVPermissions(newCSV(
+				[]rbacv1.PolicyRule{{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"*"}}},
+				[]rbacv1.PolicyRule{
+					{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"list"}},
+					{APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"operator-tls"}, Verbs: []string{"get"}},
+					{APIGroups: []string{"rbac.authorization.k8s.io"}, Resources: []string{"clusterroles"}, Verbs: []string{"bind", "escalate"}},
+				},
+			))
+			Expect(issues(result)).To(ConsistOf(
+				"namespace: "+report.RBACWildcardVerbs,
+				"cluster: "+report.RBACClusterWideSecrets,
+				"cluster: "+report.RBACEscalationVerb,
+			))
+			Expect(result.Score).To(Equal(60))
+		})
+	})
+
+	Context("running the audit", func() {
+		It("should report the findings", func() {
+			fs := afero.NewMemMapFs()
+			output := &bytes.Buffer{}
+			auditFn, _ := rbacPrivilege(context.TODO(),
+				withClient(operator.NewFakeOpClient(newCSV(nil,
+					[]rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}}},
+				))),
+				withNamespace("testns"),
+				withSubscription(&operator.SubscriptionData{Package: "testpackage"}),
+				withFilesystem(fs),
+				withReportWriter(output),
+			)
+			Expect(auditFn(context.TODO())).To(Succeed())
+
+			Expect(output.String()).To(ContainSubstring("Privilege Score: 25/100 (medium)"))
+			Expect(output.String()).To(ContainSubstring(`Finding: cluster-wide secrets access (cluster, operator): apiGroups=[""] resources=[secrets] verbs=[get]`))
+
+			report, err := afero.ReadFile(fs, "rbac_privilege_report.json")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(json.Valid(report)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,155 @@
+package capability
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/opdev/opcap/internal/logger"
+	"github.com/opdev/opcap/internal/operator"
+	operatorv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// secondaryResourceKinds are the namespaced kinds an operator usually creates on behalf of an operand
+var secondaryResourceKinds = []schema.GroupVersionKind{
+	{Group: "apps", Version: "v1", Kind: "Deployment"},
+	{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+	{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+	{Group: "batch", Version: "v1", Kind: "Job"},
+	{Group: "", Version: "v1", Kind: "Service"},
+	{Group: "", Version: "v1", Kind: "ConfigMap"},
+	{Group: "", Version: "v1", Kind: "Secret"},
+	{Group: "", Version: "v1", Kind: "PersistentVolumeClaim"},
+	{Group: "", Version: "v1", Kind: "ServiceAccount"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "Role"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "RoleBinding"},
+	{Group: "route.openshift.io", Version: "v1", Kind: "Route"},
+}
+
+// listResources lists every object of the given kind in namespace. Kinds not served by the cluster return an empty list.
+func listResources(ctx context.Context, client operator.Client, gvk schema.GroupVersionKind, namespace string) ([]unstructured.Unstructured, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+	if err := client.ListUnstructured(ctx, list, namespace); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not list %s: %v", gvk.Kind, err)
+	}
+
+	return list.Items, nil
+}
+
+// ownedResources returns the secondary resources in namespace that have an owner reference to the given UID
+func ownedResources(ctx context.Context, client operator.Client, namespace string, owner types.UID) ([]unstructured.Unstructured, error) {
+	owned := []unstructured.Unstructured{}
+
+	for _, gvk := range secondaryResourceKinds {
+		items, err := listResources(ctx, client, gvk, namespace)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			for _, ref := range item.GetOwnerReferences() {
+				if ref.UID == owner {
+					owned = append(owned, item)
+					break
+				}
+			}
+		}
+	}
+
+	return owned, nil
+}
+
+// resourceName formats an object as Kind/name for reports
+func resourceName(obj unstructured.Unstructured) string {
+	return obj.GetKind() + "/" + obj.GetName()
+}
+
+// auditNamespaces returns the operator namespace followed by the target namespaces it watches
+func auditNamespaces(options auditOptions) []string {
+	namespaces := []string{options.namespace}
+	if options.operatorGroupData == nil {
+		return namespaces
+	}
+	for _, ns := range options.operatorGroupData.TargetNamespaces {
+		if ns != options.namespace {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
+// packageCSV returns the CSV of the package under audit from the operator namespace, or nil if there is none
+func packageCSV(ctx context.Context, options auditOptions) (*operatorv1alpha1.ClusterServiceVersion, error) {
+	// gets the list of CSVs present in a particular namespace
+	csvList, err := options.client.ListClusterServiceVersions(ctx, options.namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var csv *operatorv1alpha1.ClusterServiceVersion
+	for i := range csvList.Items {
+		if strings.HasPrefix(csvList.Items[i].ObjectMeta.Name, options.subscription.Package) {
+			csv = &csvList.Items[i]
+		}
+	}
+
+	return csv, nil
+}
+
+// crdForKind returns the CRD serving the given kind, or nil if the cluster has none
+func crdForKind(ctx context.Context, client operator.Client, gvk schema.GroupVersionKind) (*apiextensionsv1.CustomResourceDefinition, error) {
+	list := &apiextensionsv1.CustomResourceDefinitionList{}
+	if err := client.ListCRDs(ctx, list); err != nil {
+		return nil, fmt.Errorf("could not list CRDs: %v", err)
+	}
+
+	for i := range list.Items {
+		if list.Items[i].Spec.Group == gvk.Group && list.Items[i].Spec.Names.Kind == gvk.Kind {
+			return &list.Items[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// crdSchema returns the OpenAPI schema of a CRD version, or nil if the version has no schema
+func crdSchema(crd *apiextensionsv1.CustomResourceDefinition, version string) *apiextensionsv1.JSONSchemaProps {
+	for _, v := range crd.Spec.Versions {
+		if v.Name == version && v.Schema != nil {
+			return v.Schema.OpenAPIV3Schema
+		}
+	}
+	return nil
+}
+
+// auditPods returns the pods running in the audit namespaces
+func auditPods(ctx context.Context, options auditOptions) ([]corev1.Pod, error) {
+	pods := []corev1.Pod{}
+	for _, ns := range auditNamespaces(options) {
+		items, err := listResources(ctx, options.client, podKind, ns)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			pod := corev1.Pod{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &pod); err != nil {
+				logger.Errorf("could not convert pod %s: %v", item.GetName(), err)
+				continue
+			}
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
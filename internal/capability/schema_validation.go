@@ -0,0 +1,54 @@
+package capability
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opdev/opcap/internal/operator"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// validateAgainstCRD validates a custom resource offline against the OpenAPI schema of its installed CRD and
+// returns the violations, each prefixed with the path of the offending field. Custom resources whose CRD is
+// not installed, or has no schema, are reported as such.
+func validateAgainstCRD(ctx context.Context, client operator.Client, obj *unstructured.Unstructured) ([]string, error) {
+	gvk := obj.GroupVersionKind()
+
+	crd, err := crdForKind(ctx, client, gvk)
+	if err != nil {
+		return nil, err
+	}
+	if crd == nil {
+		return []string{fmt.Sprintf("no CRD installed for %s", gvk.GroupKind())}, nil
+	}
+
+	openAPISchema := crdSchema(crd, gvk.Version)
+	if openAPISchema == nil {
+		return []string{fmt.Sprintf("CRD %s has no schema for version %s", crd.Name, gvk.Version)}, nil
+	}
+
+	return validateAgainstSchema(obj, openAPISchema)
+}
+
+func validateAgainstSchema(obj *unstructured.Unstructured, openAPISchema *apiextensionsv1.JSONSchemaProps) ([]string, error) {
+	internal := &apiextensions.JSONSchemaProps{}
+	if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(openAPISchema, internal, nil); err != nil {
+		return nil, fmt.Errorf("could not convert CRD schema: %v", err)
+	}
+
+	validator, _, err := validation.NewSchemaValidator(&apiextensions.CustomResourceValidation{OpenAPIV3Schema: internal})
+	if err != nil {
+		return nil, fmt.Errorf("could not build schema validator: %v", err)
+	}
+
+	violations := []string{}
+	for _, err := range validation.ValidateCustomResource(nil, obj.UnstructuredContent(), validator) {
+		violations = append(violations, err.Error())
+	}
+
+	return violations, nil
+}
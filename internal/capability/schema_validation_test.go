@@ -0,0 +1,80 @@
+package capability
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opdev/opcap/internal/operator"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("Schema validation", func() {
+	var client operator.Client
+
+	BeforeEach(func() {
+		client = operator.NewFakeOpClient(&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "examples.example.com"},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: "example.com",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: "Example", Plural: "examples"},
+				Scope: apiextensionsv1.NamespaceScoped,
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+					Name:    "v1",
+					Served:  true,
+					Storage: true,
+					Schema: &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+						Type: "object",
+						Properties: map[string]apiextensionsv1.JSONSchemaProps{
+							"spec": {
+								Type:     "object",
+								Required: []string{"size"},
+								Properties: map[string]apiextensionsv1.JSONSchemaProps{
+									"size": {Type: "integer"},
+									"mode": {Type: "string", Enum: []apiextensionsv1.JSON{{Raw: []byte(`"fast"`)}, {Raw: []byte(`"slow"`)}}},
+								},
+							},
+						},
+					}},
+				}},
+			},
+		})
+	})
+
+	newExample := func(apiVersion string, spec map[string]interface{}) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+		obj.SetAPIVersion(apiVersion)
+		obj.SetKind("Example")
+		obj.SetName("example")
+		return obj
+	}
+
+	It("should accept a valid custom resource", func() {
+		violations, err := validateAgainstCRD(context.TODO(), client, newExample("example.com/v1", map[string]interface{}{"size": int64(3), "mode": "fast"}))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(violations).To(BeEmpty())
+	})
+
+	It("should report violations with their field path", func() {
+		violations, err := validateAgainstCRD(context.TODO(), client, newExample("example.com/v1", map[string]interface{}{"mode": "medium"}))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(violations).To(HaveLen(2))
+		Expect(violations).To(ContainElement(HavePrefix("spec.size: Required value")))
+		Expect(violations).To(ContainElement(HavePrefix("spec.mode: Unsupported value")))
+	})
+
+	It("should report a missing schema", func() {
+		violations, err := validateAgainstCRD(context.TODO(), client, newExample("example.com/v2", nil))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(violations).To(ConsistOf("CRD examples.example.com has no schema for version v2"))
+	})
+
+	It("should report a missing CRD", func() {
+		obj := newExample("other.com/v1", nil)
+		violations, err := validateAgainstCRD(context.TODO(), client, obj)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(violations).To(ConsistOf("no CRD installed for Example.other.com"))
+	})
+})
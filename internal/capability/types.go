@@ -6,6 +6,7 @@ import (
 	"io"
 	"time"
 
+	"github.com/opdev/opcap/internal/bundle"
 	"github.com/opdev/opcap/internal/operator"
 	"github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"github.com/spf13/afero"
@@ -28,6 +29,14 @@ type auditOptions struct {
 	reportWriter      io.Writer
 	csvEvents         *corev1.EventList
 	detailedReports   bool
+	scaleFieldPath    string
+	allowedRegistries []string
+	// declaredOcpVersions is the com.redhat.openshift.versions annotation of the bundle under audit
+	declaredOcpVersions string
+	// bundle is the bundle under audit parsed from the bundles directory, nil when no bundles were read
+	bundle *bundle.Bundle
+	// apiWarnings accumulates the warnings the API server sent during the audits of the package
+	apiWarnings *[]string
 }
 
 type auditorOptions struct {
@@ -66,6 +75,18 @@ type auditorOptions struct {
 
 	// DetailedReports creates reports containing events and logs
 	detailedReports bool
+
+	// ScaleFieldPath is a dot separated path to the replica-like field of operands used by the OperandScale audit
+	scaleFieldPath string
+
+	// AllowedRegistries lists the registries, optionally with a repository prefix, images may be pulled from
+	allowedRegistries []string
+
+	// Bundles are read from a bundle directory to know which OpenShift versions packages declare support for
+	bundles []bundle.Bundle
+
+	// SkipUnsupported skips packages that don't declare support for the OpenShift version of the cluster
+	skipUnsupported bool
 }
 
 type (
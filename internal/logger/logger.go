@@ -57,6 +57,11 @@ func Debugf(message string, fields ...interface{}) {
 	sugarLogger.Debugf(message, fields...)
 }
 
+// Warnw exports sugared LogLevel Warn
+func Warnw(message string, fields ...interface{}) {
+	sugarLogger.Warnw(message, fields...)
+}
+
 // errorf exports Suggared Loglevel
 func Errorf(message string, fields ...interface{}) {
 	sugarLogger.Errorf(message, fields...)
@@ -50,6 +50,16 @@ var _ = Describe("Logger", func() {
 				Expect(entry.Message).To(Equal("debugf value"))
 			})
 		})
+		When("Logging with Warnw", func() {
+			It("should log the right thing", func() {
+				Warnw("warnw", "key", "value")
+				Expect(logs.Len()).To(Equal(1))
+				entry := logs.All()[0]
+				Expect(entry.Level).To(Equal(zap.WarnLevel))
+				Expect(entry.Message).To(Equal("warnw"))
+				Expect(entry.ContextMap()).To(ContainElement("value"))
+			})
+		})
 		When("Logging with Errorf", func() {
 			It("should log the right thing", func() {
 				Errorf("errorf %s", "value")
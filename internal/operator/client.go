@@ -40,11 +40,15 @@ type Client interface {
 	GetUnstructured(ctx context.Context, namespace, name string, obj *unstructured.Unstructured) error
 	DeleteUnstructured(ctx context.Context, obj *unstructured.Unstructured) error
 	UpdateUnstructured(ctx context.Context, obj *unstructured.Unstructured) error
+	ListUnstructured(ctx context.Context, list *unstructured.UnstructuredList, namespace string) error
 	ListClusterServiceVersions(ctx context.Context, namespace string) (*operatorv1alpha1.ClusterServiceVersionList, error)
+	Warnings() []string
 }
 
 type operatorClient struct {
 	Client runtimeClient.WithWatch
+	// warnings collects the Warning headers of the API server responses
+	warnings *warningCollector
 }
 
 func addSchemes(scheme *runtime.Scheme) error {
@@ -82,13 +86,19 @@ func NewOpCapClient(kubeconfig *rest.Config) (Client, error) {
 		return nil, fmt.Errorf("could not add schemes to client: %v", err)
 	}
 
+	// capture API server warnings instead of logging them, so that they can be reported per audit
+	warnings := &warningCollector{}
+	kubeconfig = rest.CopyConfig(kubeconfig)
+	kubeconfig.WarningHandler = warnings
+
 	client, err := runtimeClient.NewWithWatch(kubeconfig, runtimeClient.Options{Scheme: scheme})
 	if err != nil {
 		return nil, fmt.Errorf("could not get subscription client: %v", err)
 	}
 
 	var operatorClient Client = &operatorClient{
-		Client: client,
+		Client:   client,
+		warnings: warnings,
 	}
 	return operatorClient, nil
 }
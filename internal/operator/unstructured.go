@@ -23,3 +23,8 @@ func (c operatorClient) UpdateUnstructured(ctx context.Context, obj *unstructure
 func (c operatorClient) DeleteUnstructured(ctx context.Context, obj *unstructured.Unstructured) error {
 	return c.Client.Delete(ctx, obj, &client.DeleteOptions{})
 }
+
+// ListUnstructured lists the objects of the list's GroupVersionKind. An empty namespace lists across all namespaces.
+func (c operatorClient) ListUnstructured(ctx context.Context, list *unstructured.UnstructuredList, namespace string) error {
+	return c.Client.List(ctx, list, &client.ListOptions{Namespace: namespace})
+}
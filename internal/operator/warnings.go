@@ -0,0 +1,45 @@
+package operator
+
+import (
+	"sync"
+)
+
+// warningCollector is a rest.WarningHandler keeping the warnings the API server returns in Warning headers,
+// e.g. when a deprecated API version or field is used, until they are drained
+type warningCollector struct {
+	mu       sync.Mutex
+	warnings []string
+}
+
+// HandleWarningHeader records the warning, ignoring the ones already pending. Only 299 warnings are sent by
+// the API server, others are dropped like the default client-go handler does.
+func (c *warningCollector) HandleWarningHeader(code int, agent string, text string) {
+	if code != 299 || len(text) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, warning := range c.warnings {
+		if warning == text {
+			return
+		}
+	}
+	c.warnings = append(c.warnings, text)
+}
+
+func (c *warningCollector) drain() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	warnings := c.warnings
+	c.warnings = nil
+	return warnings
+}
+
+// Warnings returns and forgets the warnings the API server returned since the last call
+func (c operatorClient) Warnings() []string {
+	if c.warnings == nil {
+		return nil
+	}
+	return c.warnings.drain()
+}
@@ -0,0 +1,32 @@
+package operator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Warnings", func() {
+	var client operatorClient
+
+	BeforeEach(func() {
+		client = operatorClient{warnings: &warningCollector{}}
+	})
+
+	It("should collect 299 warnings once until they are drained", func() {
+		client.warnings.HandleWarningHeader(299, "-", "example.com/v1alpha1 Widget is deprecated; use example.com/v1 Widget")
+		client.warnings.HandleWarningHeader(299, "-", "example.com/v1alpha1 Widget is deprecated; use example.com/v1 Widget")
+		client.warnings.HandleWarningHeader(299, "-", "unknown field \"spec.sise\"")
+		client.warnings.HandleWarningHeader(199, "-", "miscellaneous warning")
+		client.warnings.HandleWarningHeader(299, "-", "")
+
+		Expect(client.Warnings()).To(Equal([]string{
+			"example.com/v1alpha1 Widget is deprecated; use example.com/v1 Widget",
+			"unknown field \"spec.sise\"",
+		}))
+		Expect(client.Warnings()).To(BeEmpty())
+	})
+
+	It("should not collect warnings without a collector", func() {
+		Expect(operatorClient{}.Warnings()).To(BeNil())
+	})
+})
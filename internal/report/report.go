@@ -23,6 +23,35 @@ type TemplateData struct {
 	CsvEvents       []Event
 	PodEvents       []Event
 	PodLogs         []PodLog
+
+	// SchemaViolations holds, by index in CustomResources, the CRD schema violations found before creation
+	SchemaViolations        map[int][]string
+	OperandUninstallResults []OperandUninstallResult
+	Leftovers               []Leftover
+	OperandScaleResults     []OperandScaleResult
+	Metrics                 MetricsResult
+	PrometheusRules         PrometheusRulesResult
+	OperatorEvents          OperatorEventsResult
+	OperandStatusResults    []OperandStatusResult
+	OperandAdmissionResults []OperandAdmissionResult
+	CRDCoverage             CRDCoverageResult
+	RBACPrivilege           RBACPrivilegeResult
+	PodSecurityResults      []PodSecurityResult
+	ImageChecks             []ImageCheck
+	Disconnected            DisconnectedResult
+	DeprecatedAPIs          []DeprecatedAPIUsage
+
+	// DeclaredOcpVersions is the com.redhat.openshift.versions annotation of the bundle
+	DeclaredOcpVersions string
+	// OcpSupport tells whether DeclaredOcpVersions includes OcpVersion
+	OcpSupport string
+	// OcpSupportMismatch explains how the install outcome contradicts OcpSupport, empty when it doesn't
+	OcpSupportMismatch string
+
+	// OperandWarnings holds, by index in CustomResources, the warnings the API server sent on creation
+	OperandWarnings map[int][]string
+	// APIWarnings are the warnings the API server sent during the audits of the package
+	APIWarnings []string
 }
 
 type Event struct {
@@ -31,6 +60,7 @@ type Event struct {
 	CreationTimestamp metav1.Time
 	Message           string
 	Reason            string
+	Type              string
 }
 
 type PodLog struct {
@@ -39,6 +69,24 @@ type PodLog struct {
 	PodLogs       string
 }
 
+// OperandUninstallResult holds what happened to a single operand after it was deleted
+type OperandUninstallResult struct {
+	Kind string
+	Name string
+	// Deleted is true when the operand was gone before the audit timed out
+	Deleted bool
+	// NotFound is true when the operand was already gone, the uninstall was skipped
+	NotFound     bool
+	DeletionTime time.Duration
+	// StuckFinalizers lists the finalizers still present on an operand that was not deleted in time
+	StuckFinalizers []string
+	// OrphanedResources lists owned resources, as Kind/name, that were not garbage-collected
+	OrphanedResources []string
+	// GCTimedOut is true when owned resources were still around once the audit timed out
+	GCTimedOut bool
+	Message    string
+}
+
 func replace(input, from, to string) string {
 	return strings.Replace(input, from, to, -1)
 }
@@ -87,6 +135,372 @@ func OperandInstallJsonReport(w io.Writer, data TemplateData) error {
 	return processTemplate(w, operandJsonReportTemplate, data)
 }
 
+func OperandUninstallTextReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, operandUninstallTextReportTemplate, data)
+}
+
+func OperandUninstallJsonReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, operandUninstallJsonReportTemplate, data)
+}
+
+// Leftover is a resource attributed to an operator that is still present after the operator was uninstalled
+type Leftover struct {
+	Kind      string
+	Namespace string
+	Name      string
+	// Reason explains why the resource is attributed to the operator
+	Reason string
+}
+
+func OperatorResidueTextReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, operatorResidueTextReportTemplate, data)
+}
+
+func OperatorResidueJsonReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, operatorResidueJsonReportTemplate, data)
+}
+
+// OperandScaleResult holds the outcome of scaling a single operand up and back down
+type OperandScaleResult struct {
+	Kind string
+	Name string
+	// Field is the replica-like field that was scaled, empty when none was found
+	Field     string
+	ScaleUp   ScaleStep
+	ScaleDown ScaleStep
+	Message   string
+}
+
+// ScaleStep is a single scaling operation on an operand
+type ScaleStep struct {
+	Replicas int64
+	// Workloads is the number of Deployments and StatefulSets the operand owns,
+	// WorkloadsConverged is false without any
+	Workloads          int
+	WorkloadsConverged bool
+	StatusReported     bool
+	Duration           time.Duration
+	Message            string
+}
+
+func OperandScaleTextReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, operandScaleTextReportTemplate, data)
+}
+
+func OperandScaleJsonReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, operandScaleJsonReportTemplate, data)
+}
+
+// MetricsResult holds the monitors and metrics endpoints found for an operator and its operands
+type MetricsResult struct {
+	// Monitors lists the ServiceMonitors and PodMonitors found, as Kind/name
+	Monitors  []string
+	Endpoints []MetricsEndpoint
+}
+
+// MetricsEndpoint is a single scraped metrics endpoint
+type MetricsEndpoint struct {
+	// Source is the monitor or Service, as Kind/name, the endpoint was discovered from
+	Source    string
+	Namespace string
+	Target    string
+	Scraped   bool
+	// Valid is true when the scrape parsed as the Prometheus text format
+	Valid          bool
+	MetricFamilies []string
+	Message        string
+}
+
+func MetricsTextReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, metricsTextReportTemplate, data)
+}
+
+func MetricsJsonReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, metricsJsonReportTemplate, data)
+}
+
+// PrometheusRulesResult holds the alerts found in the PrometheusRules of an operator and its operands
+type PrometheusRulesResult struct {
+	// Rules lists the PrometheusRules found, as namespace/name
+	Rules  []string
+	Alerts []AlertCheck
+	// ServiceDownAlert is true when a critical alert fires on the service being down
+	ServiceDownAlert bool
+	// Coverage is the percentage of alerts that are fully documented and syntactically valid
+	Coverage int
+}
+
+// AlertCheck is the outcome of checking a single alerting rule
+type AlertCheck struct {
+	Rule           string
+	Group          string
+	Alert          string
+	Severity       string
+	HasSummary     bool
+	HasDescription bool
+	HasRunbook     bool
+	Expr           string
+	// ExprError is set when the alert expression is not valid PromQL
+	ExprError   string
+	ServiceDown bool
+}
+
+// Complete is true when the alert has a severity, a summary, a description, a runbook and a valid expression
+func (a AlertCheck) Complete() bool {
+	return a.Severity != "" && a.HasSummary && a.HasDescription && a.HasRunbook && a.ExprError == ""
+}
+
+func PrometheusRulesTextReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, prometheusRulesTextReportTemplate, data)
+}
+
+func PrometheusRulesJsonReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, prometheusRulesJsonReportTemplate, data)
+}
+
+// OperatorEventsResult holds the events emitted by an operator, or about its operands
+type OperatorEventsResult struct {
+	Events  []Event
+	Normal  int
+	Warning int
+	// Score goes from 0, no events, to 3, meaningful Normal and Warning events
+	Score int
+}
+
+func OperatorEventsTextReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, operatorEventsTextReportTemplate, data)
+}
+
+func OperatorEventsJsonReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, operatorEventsJsonReportTemplate, data)
+}
+
+// OperandStatusResult holds how well the status of a single operand follows the Kubernetes conditions convention
+type OperandStatusResult struct {
+	Kind      string
+	Name      string
+	HasStatus bool
+	// Conditions is the number of conditions found in status.conditions
+	Conditions         int
+	Generation         int64
+	ObservedGeneration int64
+	// UpToDate is true when the status was computed for the current generation of the operand
+	UpToDate bool
+	Issues   []string
+}
+
+func OperandStatusTextReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, operandStatusTextReportTemplate, data)
+}
+
+func OperandStatusJsonReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, operandStatusJsonReportTemplate, data)
+}
+
+// OperandAdmissionResult holds how the API server answered the invalid variants of a single ALM example
+type OperandAdmissionResult struct {
+	Kind   string
+	Name   string
+	Inputs []AdmissionInput
+	// Accepted is the number of invalid variants the API server let through
+	Accepted int
+	// Inconclusive is the number of invalid variants that failed for another reason than their validation
+	Inconclusive int
+	Message      string
+}
+
+// AdmissionInput is a single invalid variant of an ALM example
+type AdmissionInput struct {
+	// Field is the dotted path of the broken field
+	Field       string
+	Description string
+	Rejected    bool
+	// RejectedBy is either schema or webhook
+	RejectedBy string
+	// Inconclusive is set when the creation failed for another reason, an unreachable webhook for instance
+	Inconclusive bool
+	Message      string
+}
+
+func OperandAdmissionTextReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, operandAdmissionTextReportTemplate, data)
+}
+
+func OperandAdmissionJsonReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, operandAdmissionJsonReportTemplate, data)
+}
+
+// CRDCoverageResult holds which CRDs owned by the CSV have an example to create operands from
+type CRDCoverageResult struct {
+	CRDs []CRDCoverage
+	// Covered is the number of owned CRDs with at least one example
+	Covered int
+}
+
+// CRDCoverage tells where, if anywhere, an owned CRD has an example
+type CRDCoverage struct {
+	Name          string
+	Kind          string
+	Version       string
+	InAlmExamples bool
+	InExtraCRs    bool
+}
+
+func CRDCoverageTextReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, crdCoverageTextReportTemplate, data)
+}
+
+func CRDCoverageJsonReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, crdCoverageJsonReportTemplate, data)
+}
+
+// Scopes of the permissions requested by a CSV
+const (
+	RBACNamespaceScope = "namespace"
+	RBACClusterScope   = "cluster"
+)
+
+// Issues found in the permissions requested by a CSV
+const (
+	RBACClusterAdmin         = "cluster-admin equivalent"
+	RBACWildcardVerbs        = "wildcard verbs"
+	RBACWildcardResources    = "wildcard resources"
+	RBACWildcardAPIGroups    = "wildcard apiGroups"
+	RBACWildcardNonResources = "wildcard nonResourceURLs"
+	RBACClusterWideSecrets   = "cluster-wide secrets access"
+	RBACEscalationVerb       = "escalate, bind or impersonate verb"
+)
+
+// RBACPrivilegeResult holds the rules of a CSV that go beyond least privilege
+type RBACPrivilegeResult struct {
+	Findings []RBACFinding
+	// Score goes from 0, least privilege, to 100, cluster-admin equivalent
+	Score int
+}
+
+// Level sums the privilege score up as low, medium or high
+func (r RBACPrivilegeResult) Level() string {
+	switch {
+	case r.Score >= 50:
+		return "high"
+	case r.Score >= 20:
+		return "medium"
+	}
+	return "low"
+}
+
+// RBACFinding is a single issue found in a rule of a CSV permission
+type RBACFinding struct {
+	// Scope is either namespace, for permissions, or cluster, for clusterPermissions
+	Scope          string
+	ServiceAccount string
+	Rule           string
+	Issue          string
+}
+
+func RBACPrivilegeTextReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, rbacPrivilegeTextReportTemplate, data)
+}
+
+func RBACPrivilegeJsonReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, rbacPrivilegeJsonReportTemplate, data)
+}
+
+// Pod Security Standards levels, from the least to the most restrictive
+const (
+	PodSecurityPrivileged = "privileged"
+	PodSecurityBaseline   = "baseline"
+	PodSecurityRestricted = "restricted"
+)
+
+// PodSecurityResult holds how a single pod complies with the Pod Security Standards
+type PodSecurityResult struct {
+	Namespace string
+	Name      string
+	// SCC is the SecurityContextConstraints the pod was admitted under, empty outside of OpenShift
+	SCC string
+	// Level is the most restrictive Pod Security Standard the pod complies with
+	Level      string
+	Violations []string
+}
+
+func PodSecurityTextReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, podSecurityTextReportTemplate, data)
+}
+
+func PodSecurityJsonReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, podSecurityJsonReportTemplate, data)
+}
+
+// ImageCheck holds how a single image is referenced by an operator and its operands
+type ImageCheck struct {
+	Image string
+	// Sources lists where the image is referenced, e.g. relatedImages/name, CSV Deployment/name or Pod/namespace/name
+	Sources         []string
+	Registry        string
+	PinnedByDigest  bool
+	RelatedImage    bool
+	AllowedRegistry bool
+}
+
+func ImageHygieneTextReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, imageHygieneTextReportTemplate, data)
+}
+
+func ImageHygieneJsonReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, imageHygieneJsonReportTemplate, data)
+}
+
+// DisconnectedResult holds whether an operator can be installed in a disconnected cluster
+type DisconnectedResult struct {
+	// Declared is true when the CSV declares the disconnected infrastructure feature
+	Declared bool
+	// UnpinnedImages are CSV images referenced by tag instead of digest
+	UnpinnedImages []string
+	// UndeclaredImages are CSV images missing from relatedImages
+	UndeclaredImages []string
+	// RuntimeUndeclaredImages are images run by pods in the audit namespaces but missing from relatedImages
+	RuntimeUndeclaredImages []string
+	Ready                   bool
+}
+
+func DisconnectedReadinessTextReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, disconnectedReadinessTextReportTemplate, data)
+}
+
+func DisconnectedReadinessJsonReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, disconnectedReadinessJsonReportTemplate, data)
+}
+
+// DeprecatedAPIUsage is a deprecated or removed Kubernetes API used by an operator or its operands
+type DeprecatedAPIUsage struct {
+	// Source is where the API is used, e.g. a bundle manifest, an ALM example, the managed fields of an object or a CRD
+	Source     string
+	APIVersion string
+	Kind       string
+	Name       string
+	// DeprecatedIn and RemovedIn are Kubernetes versions, both are empty for deprecated CRD versions
+	DeprecatedIn string
+	RemovedIn    string
+	// Replacement is the API version to migrate to, empty when there is none
+	Replacement string
+}
+
+func DeprecatedAPIsTextReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, deprecatedAPIsTextReportTemplate, data)
+}
+
+func DeprecatedAPIsJsonReport(w io.Writer, data TemplateData) error {
+	return processTemplate(w, deprecatedAPIsJsonReportTemplate, data)
+}
+
+// Support of the cluster OpenShift version by a bundle
+const (
+	OcpSupported      = "supported"
+	OcpUnsupported    = "unsupported"
+	OcpSupportUnknown = "unknown"
+)
+
 func DebugTextReport(w io.Writer, data TemplateData) error {
 	return processTemplate(w, debugTextDataTemplate, data)
 }
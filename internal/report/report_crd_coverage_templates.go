@@ -0,0 +1,18 @@
+package report
+
+const (
+	crdCoverageTextReportTemplate = `
+CRD Coverage Report
+-----------------------------------------
+Report Date: {{ now }}
+OpenShift Version: {{ .OcpVersion }}
+Package Name: {{ .Subscription.Package }}
+Channel: {{ .Subscription.Channel }}
+Install Mode: {{ .Subscription.InstallModeType }}
+Owned CRDs: {{ len .CRDCoverage.CRDs }}
+Covered CRDs: {{ .CRDCoverage.Covered }}
+{{ range .CRDCoverage.CRDs }}CRD: {{ .Name }} ({{ .Kind }} {{ .Version }}): {{ if .InAlmExamples }}alm-examples{{ if .InExtraCRs }}, extra CRs{{ end }}{{ else if .InExtraCRs }}extra CRs{{ else }}no example{{ end }}
+{{ end }}-----------------------------------------
+`
+	crdCoverageJsonReportTemplate = `{"package":"{{ .Subscription.Package }}","channel":"{{ .Subscription.Channel }}","installmode":"{{ .Subscription.InstallModeType }}","owned":{{ len .CRDCoverage.CRDs }},"covered":{{ .CRDCoverage.Covered }},"crds":[{{ range $i, $crd := .CRDCoverage.CRDs }}{{ if $i }},{{ end }}{"name":"{{ $crd.Name }}","kind":"{{ $crd.Kind }}","version":"{{ $crd.Version }}","almExamples":{{ $crd.InAlmExamples }},"extraCRs":{{ $crd.InExtraCRs }}}{{ end }}]}{{"\n"}}`
+)
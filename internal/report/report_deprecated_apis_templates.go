@@ -0,0 +1,22 @@
+package report
+
+const (
+	deprecatedAPIsTextReportTemplate = `
+Deprecated APIs Report
+-----------------------------------------
+Report Date: {{ now }}
+OpenShift Version: {{ .OcpVersion }}
+Package Name: {{ .Subscription.Package }}
+Channel: {{ .Subscription.Channel }}
+Install Mode: {{ .Subscription.InstallModeType }}
+Deprecated API Usages: {{ len .DeprecatedAPIs }}
+{{ range .DeprecatedAPIs }}{{ .APIVersion }} {{ .Kind }}{{ with .Name }} {{ . }}{{ end }}
+  Used by: {{ .Source }}
+{{ if .DeprecatedIn }}  Deprecated in: Kubernetes {{ .DeprecatedIn }}{{ with .RemovedIn }}, removed in: Kubernetes {{ . }}{{ end }}
+{{ end }}  Replacement: {{ with .Replacement }}{{ . }}{{ else }}none{{ end }}
+{{ end }}API Warnings: {{ len .APIWarnings }}
+{{ range .APIWarnings }}  {{ . }}
+{{ end }}-----------------------------------------
+`
+	deprecatedAPIsJsonReportTemplate = `{"package":"{{ .Subscription.Package }}","channel":"{{ .Subscription.Channel }}","installmode":"{{ .Subscription.InstallModeType }}","deprecatedAPIs":[{{ range $i, $usage := .DeprecatedAPIs }}{{ if $i }},{{ end }}{"source":"{{ $usage.Source }}","apiVersion":"{{ $usage.APIVersion }}","kind":"{{ $usage.Kind }}","name":"{{ $usage.Name }}","deprecatedIn":"{{ $usage.DeprecatedIn }}","removedIn":"{{ $usage.RemovedIn }}","replacement":"{{ $usage.Replacement }}"}{{ end }}],"apiWarnings":[{{ range $i, $w := .APIWarnings }}{{ if $i }},{{ end }}"{{ replace $w "\"" "'" }}"{{ end }}]}{{"\n"}}`
+)
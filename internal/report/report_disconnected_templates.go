@@ -0,0 +1,21 @@
+package report
+
+const (
+	disconnectedReadinessTextReportTemplate = `
+Disconnected Readiness Report
+-----------------------------------------
+Report Date: {{ now }}
+OpenShift Version: {{ .OcpVersion }}
+Package Name: {{ .Subscription.Package }}
+Channel: {{ .Subscription.Channel }}
+Install Mode: {{ .Subscription.InstallModeType }}
+CSV: {{ .Csv.Name }}
+Disconnected Ready: {{ .Disconnected.Ready }}
+Declares Disconnected: {{ .Disconnected.Declared }}
+{{ range .Disconnected.UnpinnedImages }}Not Pinned by Digest: {{ . }}
+{{ end }}{{ range .Disconnected.UndeclaredImages }}Not in relatedImages: {{ . }}
+{{ end }}{{ range .Disconnected.RuntimeUndeclaredImages }}Undeclared Image Pulled: {{ . }}
+{{ end }}-----------------------------------------
+`
+	disconnectedReadinessJsonReportTemplate = `{"package":"{{ .Subscription.Package }}","channel":"{{ .Subscription.Channel }}","installmode":"{{ .Subscription.InstallModeType }}","csv":"{{ .Csv.Name }}","ready":{{ .Disconnected.Ready }},"declared":{{ .Disconnected.Declared }},"unpinnedImages":[{{ range $i, $image := .Disconnected.UnpinnedImages }}{{ if $i }},{{ end }}"{{ $image }}"{{ end }}],"undeclaredImages":[{{ range $i, $image := .Disconnected.UndeclaredImages }}{{ if $i }},{{ end }}"{{ $image }}"{{ end }}],"runtimeUndeclaredImages":[{{ range $i, $image := .Disconnected.RuntimeUndeclaredImages }}{{ if $i }},{{ end }}"{{ $image }}"{{ end }}]}{{"\n"}}`
+)
@@ -0,0 +1,21 @@
+package report
+
+const (
+	imageHygieneTextReportTemplate = `
+Image Hygiene Report
+-----------------------------------------
+Report Date: {{ now }}
+OpenShift Version: {{ .OcpVersion }}
+Package Name: {{ .Subscription.Package }}
+Channel: {{ .Subscription.Channel }}
+Install Mode: {{ .Subscription.InstallModeType }}
+Images: {{ len .ImageChecks }}
+{{ range .ImageChecks }}Image: {{ .Image }}
+  Referenced by: {{ range $i, $s := .Sources }}{{ if $i }}, {{ end }}{{ $s }}{{ end }}
+  Pinned by Digest: {{ .PinnedByDigest }}
+  In relatedImages: {{ .RelatedImage }}
+  Allowed Registry: {{ .AllowedRegistry }} ({{ .Registry }})
+{{ end }}-----------------------------------------
+`
+	imageHygieneJsonReportTemplate = `{"package":"{{ .Subscription.Package }}","channel":"{{ .Subscription.Channel }}","installmode":"{{ .Subscription.InstallModeType }}","images":[{{ range $i, $image := .ImageChecks }}{{ if $i }},{{ end }}{"image":"{{ $image.Image }}","registry":"{{ $image.Registry }}","pinnedByDigest":{{ $image.PinnedByDigest }},"relatedImage":{{ $image.RelatedImage }},"allowedRegistry":{{ $image.AllowedRegistry }},"sources":[{{ range $j, $s := $image.Sources }}{{ if $j }},{{ end }}"{{ $s }}"{{ end }}]}{{ end }}]}{{"\n"}}`
+)
@@ -0,0 +1,22 @@
+package report
+
+const (
+	metricsTextReportTemplate = `
+Metrics Report
+-----------------------------------------
+Report Date: {{ now }}
+OpenShift Version: {{ .OcpVersion }}
+Package Name: {{ .Subscription.Package }}
+Channel: {{ .Subscription.Channel }}
+Install Mode: {{ .Subscription.InstallModeType }}
+Monitors: {{ if .Metrics.Monitors }}{{ range $i, $m := .Metrics.Monitors }}{{ if $i }}, {{ end }}{{ $m }}{{ end }}{{ else }}none{{ end }}
+{{ range .Metrics.Endpoints }}Endpoint: {{ .Namespace }}/{{ .Target }} (from {{ .Source }})
+  Scraped: {{ .Scraped }}
+  Valid: {{ .Valid }}
+{{ if .MetricFamilies }}  Metric Families: {{ len .MetricFamilies }}
+{{ end }}{{ if .Message }}  Message: {{ .Message }}
+{{ end }}{{ else }}No metrics endpoints found
+{{ end }}-----------------------------------------
+`
+	metricsJsonReportTemplate = `{"package":"{{ .Subscription.Package }}","channel":"{{ .Subscription.Channel }}","installmode":"{{ .Subscription.InstallModeType }}","monitors":[{{ range $i, $m := .Metrics.Monitors }}{{ if $i }},{{ end }}"{{ $m }}"{{ end }}],"endpoints":[{{ range $i, $e := .Metrics.Endpoints }}{{ if $i }},{{ end }}{"source":"{{ $e.Source }}","namespace":"{{ $e.Namespace }}","target":"{{ $e.Target }}","scraped":{{ $e.Scraped }},"valid":{{ $e.Valid }},"metricFamilies":[{{ range $j, $f := $e.MetricFamilies }}{{ if $j }},{{ end }}"{{ $f }}"{{ end }}],"message":"{{ replace $e.Message "\"" "'" }}"}{{ end }}]}{{"\n"}}`
+)
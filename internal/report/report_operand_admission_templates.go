@@ -0,0 +1,28 @@
+package report
+
+const (
+	operandAdmissionTextReportTemplate = `
+{{ with $dot := . }}
+{{ range $index, $value := .OperandAdmissionResults }}
+
+Operand Admission Report
+-----------------------------------------
+Report Date: {{ now }}
+OpenShift Version: {{ $dot.OcpVersion }}
+Package Name: {{ $dot.Subscription.Package }}
+Operand Kind: {{ $value.Kind }}
+Operand Name: {{ $value.Name }}
+Invalid Inputs: {{ len $value.Inputs }}
+Accepted Invalid Inputs: {{ $value.Accepted }}
+Inconclusive Inputs: {{ $value.Inconclusive }}
+{{ range $value.Inputs }}{{ if .Rejected }}Rejected by {{ .RejectedBy }}{{ else if .Inconclusive }}INCONCLUSIVE{{ else }}ACCEPTED{{ end }}: {{ .Field }} ({{ .Description }})
+{{ end }}{{ if $value.Message }}Message: {{ $value.Message }}
+{{ end }}-----------------------------------------
+{{ else }}
+No operands to test
+{{ end }}
+{{ end }}
+`
+
+	operandAdmissionJsonReportTemplate = `{{ with $dot := . }}{{ range $index, $value := .OperandAdmissionResults }}{"package":"{{ $dot.Subscription.Package }}","Operand Kind":"{{ $value.Kind }}","Operand Name":"{{ $value.Name }}","message":"{{ if $value.Message }}{{ $value.Message }}{{ else if $value.Accepted }}accepted invalid inputs{{ else if $value.Inconclusive }}inconclusive invalid inputs{{ else }}rejected all invalid inputs{{ end }}","accepted":{{ $value.Accepted }},"inconclusive":{{ $value.Inconclusive }},"inputs":[{{ range $i, $input := $value.Inputs }}{{ if $i }},{{ end }}{"field":"{{ $input.Field }}","description":"{{ $input.Description }}","rejected":{{ $input.Rejected }},"rejectedBy":"{{ $input.RejectedBy }}","inconclusive":{{ $input.Inconclusive }}}{{ end }}]}{{"\n"}}{{ end }}{{ end }}`
+)
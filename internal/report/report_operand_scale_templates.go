@@ -0,0 +1,35 @@
+package report
+
+const (
+	operandScaleTextReportTemplate = `
+{{ with $dot := . }}
+{{ range $index, $value := .OperandScaleResults }}
+
+Operand Scale Report
+-----------------------------------------
+Report Date: {{ now }}
+OpenShift Version: {{ $dot.OcpVersion }}
+Package Name: {{ $dot.Subscription.Package }}
+Operand Kind: {{ $value.Kind }}
+Operand Name: {{ $value.Name }}
+{{ if $value.Field }}Replica Field: {{ $value.Field }}
+{{ with $value.ScaleUp }}Scale Up To: {{ .Replicas }}
+  Workloads Converged: {{ if .Workloads }}{{ .WorkloadsConverged }}{{ else }}no workloads{{ end }}
+  Status Reported: {{ .StatusReported }}
+  Duration: {{ .Duration }}
+{{ if .Message }}  Message: {{ .Message }}
+{{ end }}{{ end }}{{ with $value.ScaleDown }}Scale Down To: {{ .Replicas }}
+  Workloads Converged: {{ if .Workloads }}{{ .WorkloadsConverged }}{{ else }}no workloads{{ end }}
+  Status Reported: {{ .StatusReported }}
+  Duration: {{ .Duration }}
+{{ if .Message }}  Message: {{ .Message }}
+{{ end }}{{ end }}{{ else }}Message: {{ $value.Message }}
+{{ end }}-----------------------------------------
+{{ else }}
+No operands to scale
+{{ end }}
+{{ end }}
+`
+
+	operandScaleJsonReportTemplate = `{{ with $dot := . }}{{ range $index, $value := .OperandScaleResults }}{"package":"{{ $dot.Subscription.Package }}","Operand Kind":"{{ $value.Kind }}","Operand Name":"{{ $value.Name }}","field":"{{ $value.Field }}","message":"{{ if not $value.Field }}not scalable{{ else if or (not $value.ScaleUp.Workloads) (not $value.ScaleDown.Workloads) }}no workloads{{ else if and $value.ScaleUp.WorkloadsConverged $value.ScaleUp.StatusReported $value.ScaleDown.WorkloadsConverged $value.ScaleDown.StatusReported }}scaled{{ else }}not converged{{ end }}"{{ if $value.Field }},"scaleUp":{"replicas":{{ $value.ScaleUp.Replicas }},"workloads":{{ $value.ScaleUp.Workloads }},"workloadsConverged":{{ $value.ScaleUp.WorkloadsConverged }},"statusReported":{{ $value.ScaleUp.StatusReported }},"duration":"{{ $value.ScaleUp.Duration }}"},"scaleDown":{"replicas":{{ $value.ScaleDown.Replicas }},"workloads":{{ $value.ScaleDown.Workloads }},"workloadsConverged":{{ $value.ScaleDown.WorkloadsConverged }},"statusReported":{{ $value.ScaleDown.StatusReported }},"duration":"{{ $value.ScaleDown.Duration }}"}{{ end }}}{{"\n"}}{{ end }}{{ end }}`
+)
@@ -0,0 +1,28 @@
+package report
+
+const (
+	operandStatusTextReportTemplate = `
+{{ with $dot := . }}
+{{ range $index, $value := .OperandStatusResults }}
+
+Operand Status Report
+-----------------------------------------
+Report Date: {{ now }}
+OpenShift Version: {{ $dot.OcpVersion }}
+Package Name: {{ $dot.Subscription.Package }}
+Operand Kind: {{ $value.Kind }}
+Operand Name: {{ $value.Name }}
+Status: {{ if $value.HasStatus }}Present{{ else }}Missing{{ end }}
+Conditions: {{ $value.Conditions }}
+Generation: {{ $value.Generation }}
+Observed Generation: {{ $value.ObservedGeneration }}
+{{ range $value.Issues }}Issue: {{ . }}
+{{ end }}-----------------------------------------
+{{ else }}
+No operands to check
+{{ end }}
+{{ end }}
+`
+
+	operandStatusJsonReportTemplate = `{{ with $dot := . }}{{ range $index, $value := .OperandStatusResults }}{"package":"{{ $dot.Subscription.Package }}","Operand Kind":"{{ $value.Kind }}","Operand Name":"{{ $value.Name }}","message":"{{ if not $value.HasStatus }}no status{{ else if not $value.UpToDate }}stale{{ else if $value.Issues }}non-standard{{ else }}valid{{ end }}","conditions":{{ $value.Conditions }},"generation":{{ $value.Generation }},"observedGeneration":{{ $value.ObservedGeneration }},"issues":[{{ range $i, $issue := $value.Issues }}{{ if $i }},{{ end }}"{{ replace $issue "\"" "'" }}"{{ end }}]}{{"\n"}}{{ end }}{{ end }}`
+)
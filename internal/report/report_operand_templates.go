@@ -13,6 +13,12 @@ Package Name: {{ $dot.Subscription.Package }}
 Operand Kind: {{ kind $value }}
 Operand Name: {{ name $value }}
 Operand Creation: {{ if gt $dot.OperandCount 0 }}Succeeded{{ else }}Failed{{ end }}
+{{ with index $dot.SchemaViolations $index }}Schema Violations:
+{{ range . }}  {{ . }}
+{{ end }}{{ end -}}
+{{ with index $dot.OperandWarnings $index }}API Warnings:
+{{ range . }}  {{ . }}
+{{ end }}{{ end -}}
 -----------------------------------------
 {{ else }}
 No custom resources
@@ -20,5 +26,5 @@ No custom resources
 {{ end }}
 `
 
-	operandJsonReportTemplate = `{{with $dot := .}}{{range $index, $value := .CustomResources }}{"package":"{{ $dot.Subscription.Package }}","Operand Kind":"{{ kind $value }}","Operand Name":"{{ name $value }}","message":"{{ if gt $dot.OperandCount 0 }}created{{ else }}failed{{ end }}"}{{ end }}{{ end }}{{"\n"}}`
+	operandJsonReportTemplate = `{{with $dot := .}}{{range $index, $value := .CustomResources }}{"package":"{{ $dot.Subscription.Package }}","Operand Kind":"{{ kind $value }}","Operand Name":"{{ name $value }}","message":"{{ if gt $dot.OperandCount 0 }}created{{ else }}failed{{ end }}"{{ with index $dot.SchemaViolations $index }},"schemaViolations":[{{ range $i, $v := . }}{{ if $i }},{{ end }}"{{ replace $v "\"" "'" }}"{{ end }}]{{ end }}{{ with index $dot.OperandWarnings $index }},"apiWarnings":[{{ range $i, $w := . }}{{ if $i }},{{ end }}"{{ replace $w "\"" "'" }}"{{ end }}]{{ end }}}{{ end }}{{ end }}{{"\n"}}`
 )
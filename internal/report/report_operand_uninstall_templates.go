@@ -0,0 +1,29 @@
+package report
+
+const (
+	operandUninstallTextReportTemplate = `
+{{ with $dot := . }}
+{{ range $index, $value := .OperandUninstallResults }}
+
+Operand Uninstall Report
+-----------------------------------------
+Report Date: {{ now }}
+OpenShift Version: {{ $dot.OcpVersion }}
+Package Name: {{ $dot.Subscription.Package }}
+Operand Kind: {{ $value.Kind }}
+Operand Name: {{ $value.Name }}
+Operand Deletion: {{ if $value.Deleted }}Succeeded{{ else if $value.NotFound }}Skipped{{ else }}Failed{{ end }}
+{{ if $value.Deleted }}Deletion Time: {{ $value.DeletionTime }}
+{{ end }}{{ if $value.StuckFinalizers }}Stuck Finalizers: {{ range $i, $f := $value.StuckFinalizers }}{{ if $i }}, {{ end }}{{ $f }}{{ end }}
+{{ end }}{{ if $value.GCTimedOut }}Garbage Collection: Timed out
+{{ end }}{{ if $value.OrphanedResources }}Orphaned Resources: {{ range $i, $r := $value.OrphanedResources }}{{ if $i }}, {{ end }}{{ $r }}{{ end }}
+{{ end }}{{ if $value.Message }}Message: {{ $value.Message }}
+{{ end }}-----------------------------------------
+{{ else }}
+No operands to uninstall
+{{ end }}
+{{ end }}
+`
+
+	operandUninstallJsonReportTemplate = `{{ with $dot := . }}{{ range $index, $value := .OperandUninstallResults }}{"package":"{{ $dot.Subscription.Package }}","Operand Kind":"{{ $value.Kind }}","Operand Name":"{{ $value.Name }}","message":"{{ if $value.NotFound }}not found{{ else if not $value.Deleted }}stuck{{ else if $value.OrphanedResources }}orphaned{{ else }}deleted{{ end }}","deletionTime":"{{ $value.DeletionTime }}","gcTimedOut":{{ $value.GCTimedOut }},"stuckFinalizers":[{{ range $i, $f := $value.StuckFinalizers }}{{ if $i }},{{ end }}"{{ $f }}"{{ end }}],"orphanedResources":[{{ range $i, $r := $value.OrphanedResources }}{{ if $i }},{{ end }}"{{ $r }}"{{ end }}]}{{"\n"}}{{ end }}{{ end }}`
+)
@@ -0,0 +1,19 @@
+package report
+
+const (
+	operatorEventsTextReportTemplate = `
+Operator Events Report
+-----------------------------------------
+Report Date: {{ now }}
+OpenShift Version: {{ .OcpVersion }}
+Package Name: {{ .Subscription.Package }}
+Channel: {{ .Subscription.Channel }}
+Install Mode: {{ .Subscription.InstallModeType }}
+Normal Events: {{ .OperatorEvents.Normal }}
+Warning Events: {{ .OperatorEvents.Warning }}
+Score: {{ .OperatorEvents.Score }}/3
+{{ range .OperatorEvents.Events }}Event: {{ .Type }} {{ .Reason }} {{ .InvolvedObjkind }}/{{ .InvolvedObjName }}: {{ .Message }}
+{{ end }}-----------------------------------------
+`
+	operatorEventsJsonReportTemplate = `{"package":"{{ .Subscription.Package }}","channel":"{{ .Subscription.Channel }}","installmode":"{{ .Subscription.InstallModeType }}","normal":{{ .OperatorEvents.Normal }},"warning":{{ .OperatorEvents.Warning }},"score":{{ .OperatorEvents.Score }},"events":[{{ range $i, $e := .OperatorEvents.Events }}{{ if $i }},{{ end }}{"type":"{{ $e.Type }}","reason":"{{ $e.Reason }}","kind":"{{ $e.InvolvedObjkind }}","name":"{{ $e.InvolvedObjName }}","message":"{{ replace $e.Message "\n" " " }}"}{{ end }}]}{{"\n"}}`
+)
@@ -0,0 +1,17 @@
+package report
+
+const (
+	operatorResidueTextReportTemplate = `
+Operator Uninstall Residue Report
+-----------------------------------------
+Report Date: {{ now }}
+OpenShift Version: {{ .OcpVersion }}
+Package Name: {{ .Subscription.Package }}
+Channel: {{ .Subscription.Channel }}
+Install Mode: {{ .Subscription.InstallModeType }}
+Result: {{ if .Leftovers }}residue found{{ else }}clean{{ end }}
+{{ range .Leftovers }}Leftover: {{ .Kind }} {{ if .Namespace }}{{ .Namespace }}/{{ end }}{{ .Name }} ({{ .Reason }})
+{{ end }}-----------------------------------------
+`
+	operatorResidueJsonReportTemplate = `{"package":"{{ .Subscription.Package }}","channel":"{{ .Subscription.Channel }}","installmode":"{{ .Subscription.InstallModeType }}","message":"{{ if .Leftovers }}residue{{ else }}clean{{ end }}","leftovers":[{{ range $i, $l := .Leftovers }}{{ if $i }},{{ end }}{"kind":"{{ $l.Kind }}","namespace":"{{ $l.Namespace }}","name":"{{ $l.Name }}","reason":"{{ $l.Reason }}"}{{ end }}]}{{"\n"}}`
+)
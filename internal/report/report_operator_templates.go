@@ -6,6 +6,15 @@ Operator Install Report
 -----------------------------------------
 Report Date: {{ now }}
 OpenShift Version: {{ .OcpVersion }}
+{{- with .DeclaredOcpVersions }}
+Declared OpenShift Versions: {{ . }}
+{{- end }}
+{{- with .OcpSupport }}
+OpenShift Version Support: {{ . }}
+{{- end }}
+{{- with .OcpSupportMismatch }}
+OpenShift Version Mismatch: {{ . }}
+{{- end }}
 Package Name: {{ .Subscription.Package }}
 Channel: {{ .Subscription.Channel }}
 Catalog Source: {{ .Subscription.CatalogSource }}
@@ -16,5 +25,5 @@ Reason: {{ .Csv.Status.Reason }}
 {{ end }}
 -----------------------------------------
 `
-	operatorJsonReportTemplate = `{"level":"info","message":"{{ if .CsvTimeout }}timeout{{ else }}{{ .Csv.Status.Phase }}{{ end }}","package":"{{ .Subscription.Package }}","channel":"{{ .Subscription.Channel }}","installmode":"{{ .Subscription.InstallModeType }}"}{{"\n"}}`
+	operatorJsonReportTemplate = `{"level":"info","message":"{{ if .CsvTimeout }}timeout{{ else }}{{ .Csv.Status.Phase }}{{ end }}","package":"{{ .Subscription.Package }}","channel":"{{ .Subscription.Channel }}","installmode":"{{ .Subscription.InstallModeType }}"{{ with .DeclaredOcpVersions }},"declaredOcpVersions":"{{ . }}"{{ end }}{{ with .OcpSupport }},"ocpSupport":"{{ . }}"{{ end }}{{ with .OcpSupportMismatch }},"ocpSupportMismatch":"{{ . }}"{{ end }}}{{"\n"}}`
 )
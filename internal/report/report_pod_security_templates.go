@@ -0,0 +1,25 @@
+package report
+
+const (
+	podSecurityTextReportTemplate = `
+{{ with $dot := . }}
+{{ range $index, $value := .PodSecurityResults }}
+
+Pod Security Report
+-----------------------------------------
+Report Date: {{ now }}
+OpenShift Version: {{ $dot.OcpVersion }}
+Package Name: {{ $dot.Subscription.Package }}
+Pod: {{ $value.Namespace }}/{{ $value.Name }}
+SCC: {{ if $value.SCC }}{{ $value.SCC }}{{ else }}none{{ end }}
+Pod Security Level: {{ $value.Level }}
+{{ range $value.Violations }}Violation: {{ . }}
+{{ end }}-----------------------------------------
+{{ else }}
+No pods to audit
+{{ end }}
+{{ end }}
+`
+
+	podSecurityJsonReportTemplate = `{{ with $dot := . }}{{ range $index, $value := .PodSecurityResults }}{"package":"{{ $dot.Subscription.Package }}","namespace":"{{ $value.Namespace }}","pod":"{{ $value.Name }}","scc":"{{ $value.SCC }}","level":"{{ $value.Level }}","violations":[{{ range $i, $v := $value.Violations }}{{ if $i }},{{ end }}"{{ $v }}"{{ end }}]}{{"\n"}}{{ end }}{{ end }}`
+)
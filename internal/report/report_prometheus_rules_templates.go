@@ -0,0 +1,25 @@
+package report
+
+const (
+	prometheusRulesTextReportTemplate = `
+Prometheus Rules Report
+-----------------------------------------
+Report Date: {{ now }}
+OpenShift Version: {{ .OcpVersion }}
+Package Name: {{ .Subscription.Package }}
+Channel: {{ .Subscription.Channel }}
+Install Mode: {{ .Subscription.InstallModeType }}
+PrometheusRules: {{ if .PrometheusRules.Rules }}{{ range $i, $r := .PrometheusRules.Rules }}{{ if $i }}, {{ end }}{{ $r }}{{ end }}{{ else }}none{{ end }}
+Alerts: {{ len .PrometheusRules.Alerts }}
+Service Down Alert: {{ .PrometheusRules.ServiceDownAlert }}
+Coverage: {{ .PrometheusRules.Coverage }}%
+{{ range .PrometheusRules.Alerts }}{{ if not .Complete }}Alert: {{ .Alert }} ({{ .Rule }}/{{ .Group }})
+{{ if not .Severity }}  missing severity label
+{{ end }}{{ if not .HasSummary }}  missing summary annotation
+{{ end }}{{ if not .HasDescription }}  missing description annotation
+{{ end }}{{ if not .HasRunbook }}  missing runbook_url annotation
+{{ end }}{{ if .ExprError }}  invalid expression: {{ .ExprError }}
+{{ end }}{{ end }}{{ end }}-----------------------------------------
+`
+	prometheusRulesJsonReportTemplate = `{"package":"{{ .Subscription.Package }}","channel":"{{ .Subscription.Channel }}","installmode":"{{ .Subscription.InstallModeType }}","rules":[{{ range $i, $r := .PrometheusRules.Rules }}{{ if $i }},{{ end }}"{{ $r }}"{{ end }}],"serviceDownAlert":{{ .PrometheusRules.ServiceDownAlert }},"coverage":{{ .PrometheusRules.Coverage }},"alerts":[{{ range $i, $a := .PrometheusRules.Alerts }}{{ if $i }},{{ end }}{"rule":"{{ $a.Rule }}","group":"{{ $a.Group }}","alert":"{{ $a.Alert }}","severity":"{{ $a.Severity }}","summary":{{ $a.HasSummary }},"description":{{ $a.HasDescription }},"runbook":{{ $a.HasRunbook }},"exprError":"{{ replace $a.ExprError "\"" "'" }}"}{{ end }}]}{{"\n"}}`
+)
@@ -0,0 +1,18 @@
+package report
+
+const (
+	rbacPrivilegeTextReportTemplate = `
+RBAC Privilege Report
+-----------------------------------------
+Report Date: {{ now }}
+OpenShift Version: {{ .OcpVersion }}
+Package Name: {{ .Subscription.Package }}
+Channel: {{ .Subscription.Channel }}
+Install Mode: {{ .Subscription.InstallModeType }}
+CSV: {{ .Csv.Name }}
+Privilege Score: {{ .RBACPrivilege.Score }}/100 ({{ .RBACPrivilege.Level }})
+{{ range .RBACPrivilege.Findings }}Finding: {{ .Issue }} ({{ .Scope }}, {{ .ServiceAccount }}): {{ .Rule }}
+{{ end }}-----------------------------------------
+`
+	rbacPrivilegeJsonReportTemplate = `{"package":"{{ .Subscription.Package }}","channel":"{{ .Subscription.Channel }}","installmode":"{{ .Subscription.InstallModeType }}","csv":"{{ .Csv.Name }}","score":{{ .RBACPrivilege.Score }},"level":"{{ .RBACPrivilege.Level }}","findings":[{{ range $i, $f := .RBACPrivilege.Findings }}{{ if $i }},{{ end }}{"issue":"{{ $f.Issue }}","scope":"{{ $f.Scope }}","serviceAccount":"{{ $f.ServiceAccount }}","rule":"{{ replace $f.Rule "\"" "'" }}"}{{ end }}]}{{"\n"}}`
+)
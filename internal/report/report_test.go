@@ -31,13 +31,13 @@ var _ = Describe("Report", func() {
 		BeforeEach(func() {
 			DeferCleanup(w.Reset)
 			data = TemplateData{
-				OcpVersion: "",
+				OcpVersion: "4.11",
 				Subscription: operator.SubscriptionData{
 					Name:            "testsub",
 					Channel:         "test",
 					CatalogSource:   "testcatalog",
 					Package:         "testpackage",
-					InstallModeType: "v1alpha1.InstallModeTypeAllNamespaces",
+					InstallModeType: "AllNamespaces",
 				},
 				Csv: &v1alpha1.ClusterServiceVersion{
 					Status: v1alpha1.ClusterServiceVersionStatus{
@@ -85,6 +85,16 @@ var _ = Describe("Report", func() {
 						Expect(w.String()).To(MatchJSON(`{"level":"info","message":"timeout","package":"testpackage","channel":"test","installmode":"AllNamespaces"}`))
 					})
 				})
+				When("given the declared OpenShift versions", func() {
+					BeforeEach(func() {
+						data.DeclaredOcpVersions = "v4.8-v4.10"
+						data.OcpSupport = "unsupported"
+					})
+					It("should report them next to the support", func() {
+						Expect(OperatorInstallJsonReport(&w, data)).To(Succeed())
+						Expect(w.String()).To(MatchJSON(`{"level":"info","message":"Succeeded","package":"testpackage","channel":"test","installmode":"AllNamespaces","declaredOcpVersions":"v4.8-v4.10","ocpSupport":"unsupported"}`))
+					})
+				})
 			})
 			When("generating a text report", func() {
 				When("given successful data", func() {
@@ -122,12 +132,22 @@ var _ = Describe("Report", func() {
 				When("given no operands", func() {
 					BeforeEach(func() {
 						data.Operands = []unstructured.Unstructured{}
+						data.OperandCount = 0
 					})
 					It("should report failed", func() {
 						Expect(OperandInstallJsonReport(&w, data)).To(Succeed())
 						Expect(w.String()).To(MatchJSON(`{"package":"testpackage","Operand Kind":"testkind","Operand Name":"testname","message":"failed"}`))
 					})
 				})
+				When("given schema violations", func() {
+					BeforeEach(func() {
+						data.SchemaViolations = map[int][]string{0: {`spec.size: Invalid value: "string": spec.size in body must be of type integer: "string"`}}
+					})
+					It("should list the violations", func() {
+						Expect(OperandInstallJsonReport(&w, data)).To(Succeed())
+						Expect(w.String()).To(MatchJSON(`{"package":"testpackage","Operand Kind":"testkind","Operand Name":"testname","message":"created","schemaViolations":["spec.size: Invalid value: 'string': spec.size in body must be of type integer: 'string'"]}`))
+					})
+				})
 			})
 			When("generating a text report", func() {
 				When("given successful data", func() {
@@ -143,6 +163,7 @@ var _ = Describe("Report", func() {
 				When("given no operands", func() {
 					BeforeEach(func() {
 						data.Operands = []unstructured.Unstructured{}
+						data.OperandCount = 0
 					})
 					It("should report failed", func() {
 						Expect(OperandInstallTextReport(&w, data)).To(Succeed())